@@ -0,0 +1,54 @@
+package main
+
+import (
+	"domogeek/pkg/calendar"
+	"domogeek/pkg/params"
+	"fmt"
+	"time"
+)
+
+// checkCaldav prints the summary of the CalDAV event matching the summary pattern of each day between from and to,
+// today and 30 days later by default. It returns false if caldav can't be queried or if no event matches.
+func checkCaldav(cal *calendar.Calendar, now time.Time, from, to string) bool {
+	y, m, d := now.In(cal.Location).Date()
+	start := time.Date(y, m, d, 0, 0, 0, 0, cal.Location)
+	if from != "" {
+		var err error
+		start, err = time.ParseInLocation(params.DateLayout, from, cal.Location)
+		if err != nil {
+			fmt.Printf("KO: invalid check-from '%v', expected format is YYYY-MM-DD\n", from)
+			return false
+		}
+	}
+	end := start.AddDate(0, 0, 30)
+	if to != "" {
+		var err error
+		end, err = time.ParseInLocation(params.DateLayout, to, cal.Location)
+		if err != nil {
+			fmt.Printf("KO: invalid check-to '%v', expected format is YYYY-MM-DD\n", to)
+			return false
+		}
+	}
+
+	// a single CalDAV query for the whole range
+	ranged := cal.ForRange(start, end)
+	matches := 0
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		event, err := ranged.CaldavEventAt(day)
+		if err != nil {
+			fmt.Printf("KO: unable to query caldav for %v: %v\n", day.Format(params.DateLayout), err)
+			return false
+		}
+		if event != nil {
+			matches++
+			fmt.Printf("%v: %v\n", day.Format(params.DateLayout), event.Summary)
+		}
+	}
+
+	if matches == 0 {
+		fmt.Printf("KO: no event matches between %v and %v\n", start.Format(params.DateLayout), end.Format(params.DateLayout))
+		return false
+	}
+	fmt.Printf("OK: %d days match between %v and %v\n", matches, start.Format(params.DateLayout), end.Format(params.DateLayout))
+	return true
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"domogeek/pkg/calendar"
+	"github.com/dolanor/caldav-go/icalendar/components"
+	"github.com/dolanor/caldav-go/icalendar/values"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// captureStdout returns what f prints on the standard output
+func captureStdout(t *testing.T, f func()) string {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("unable to create pipe: %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() {
+		os.Stdout = stdout
+	}()
+	f()
+	_ = w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("unable to read output: %v", err)
+	}
+	return string(out)
+}
+
+func TestCheckCaldav(t *testing.T) {
+	cdav := &CountingCaldav{
+		MockCaldav: MockCaldav{
+			events: []*components.Event{
+				{
+					UID:       "1",
+					DateStart: values.NewDateTime(time.Date(2024, time.May, 13, 0, 0, 0, 0, time.UTC)),
+					DateEnd:   values.NewDateTime(time.Date(2024, time.May, 15, 0, 0, 0, 0, time.UTC)),
+					Summary:   "Holidays: Congés d'été",
+				},
+			},
+		},
+	}
+	cal := newTestCalendar(t,
+		calendar.WithCaldav(cdav),
+		calendar.WithCaldavSummaryPattern("Holidays"),
+		calendar.WithCaldavNameExtractor(func(summary string) string {
+			return strings.TrimPrefix(summary, "Holidays: ")
+		}),
+	)
+
+	var ok bool
+	out := captureStdout(t, func() {
+		ok = checkCaldav(cal, time.Now(), "2024-05-01", "2024-05-31")
+	})
+	if !ok {
+		t.Errorf("check should succeed: %v", out)
+	}
+	if cdav.queries != 1 {
+		t.Errorf("bad caldav queries count, expected:1 ; actual:%v", cdav.queries)
+	}
+	for _, want := range []string{"2024-05-13: Holidays: Congés d'été", "2024-05-14: Holidays: Congés d'été", "OK: 2 days match"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output should contain '%v': %v", want, out)
+		}
+	}
+
+	out = captureStdout(t, func() {
+		ok = checkCaldav(cal, time.Now(), "2024-06-01", "2024-06-30")
+	})
+	if ok || !strings.Contains(out, "KO: no event matches") {
+		t.Errorf("check should fail without matching event: %v", out)
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"domogeek/pkg/calendar"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// reloadableFlags are the flags of the config file applied again on SIGHUP, other flags are read at startup only
+var reloadableFlags = map[string]bool{
+	"holidays-file":           true,
+	"caldav-summary-pattern":  true,
+	"caldav-summary-patterns": true,
+	"region":                  true,
+}
+
+// readConfigFile reads the flag values of path, a name=value line per flag, such as region=corse. Empty lines and
+// lines starting with # are ignored.
+func readConfigFile(path string) (map[string]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read config file '%v': %w", path, err)
+	}
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid line %d of config file '%v', name=value expected", n, path)
+		}
+		values[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	return values, scanner.Err()
+}
+
+// applyConfig sets the flags of fs to values. On reload, only reloadableFlags are set, the names of the other flags
+// whose value changed are returned to be reported as ignored.
+func applyConfig(fs *flag.FlagSet, values map[string]string, reload bool) ([]string, error) {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		if fs.Lookup(name) == nil {
+			return nil, fmt.Errorf("unknown flag '%v' in config file", name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var ignored []string
+	for _, name := range names {
+		if reload && !reloadableFlags[name] {
+			if fs.Lookup(name).Value.String() != values[name] {
+				ignored = append(ignored, name)
+			}
+			continue
+		}
+		if err := fs.Set(name, values[name]); err != nil {
+			return nil, fmt.Errorf("invalid flag '%v' in config file: %w", name, err)
+		}
+	}
+	return ignored, nil
+}
+
+// summaryPatterns returns the summary patterns of the caldav-summary-pattern and caldav-summary-patterns flags, the
+// latter replacing the former when set
+func summaryPatterns(pattern, patterns string) []string {
+	if patterns == "" {
+		return []string{pattern}
+	}
+	var result []string
+	for _, p := range strings.Split(patterns, ",") {
+		result = append(result, strings.TrimSpace(p))
+	}
+	return result
+}
+
+// parseRegion returns the region named value, one of calendar.Regions
+func parseRegion(value string) (calendar.Region, error) {
+	for _, region := range calendar.Regions {
+		if string(region) == value {
+			return region, nil
+		}
+	}
+	return "", fmt.Errorf("unknown region '%v'", value)
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"domogeek/pkg/calendar"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "domogeek.conf")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("unable to write config file: %v", err)
+	}
+	return path
+}
+
+func TestReadConfigFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name:    "values",
+			content: "# comment\nregion = corse\n\ncaldav-summary-patterns=Congés,RTT\n",
+			want:    map[string]string{"region": "corse", "caldav-summary-patterns": "Congés,RTT"},
+		},
+		{
+			name:    "empty value",
+			content: "holidays-file=\n",
+			want:    map[string]string{"holidays-file": ""},
+		},
+		{
+			name:    "invalid line",
+			content: "region corse\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := readConfigFile(writeConfigFile(t, tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("bad error, expected error:%v ; actual:%v", tt.wantErr, err)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(values, tt.want) {
+				t.Errorf("bad values, expected:%v ; actual:%v", tt.want, values)
+			}
+		})
+	}
+}
+
+func TestReadConfigFile_Missing(t *testing.T) {
+	if _, err := readConfigFile(filepath.Join(t.TempDir(), "missing.conf")); err == nil {
+		t.Errorf("missing config file must fail")
+	}
+}
+
+func TestApplyConfig(t *testing.T) {
+	newFlagSet := func() *flag.FlagSet {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.String("region", "metropole", "")
+		fs.String("holidays-file", "", "")
+		fs.Int("port", 8080, "")
+		return fs
+	}
+
+	tests := []struct {
+		name        string
+		values      map[string]string
+		reload      bool
+		wantErr     bool
+		wantIgnored []string
+		wantRegion  string
+		wantPort    string
+	}{
+		{
+			name:       "startup",
+			values:     map[string]string{"region": "corse", "port": "9090"},
+			wantRegion: "corse",
+			wantPort:   "9090",
+		},
+		{
+			name:        "reload",
+			values:      map[string]string{"region": "corse", "port": "9090"},
+			reload:      true,
+			wantIgnored: []string{"port"},
+			wantRegion:  "corse",
+			wantPort:    "8080",
+		},
+		{
+			name:       "reload unchanged",
+			values:     map[string]string{"region": "corse", "port": "8080"},
+			reload:     true,
+			wantRegion: "corse",
+			wantPort:   "8080",
+		},
+		{
+			name:    "unknown flag",
+			values:  map[string]string{"unknown": "value"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid value",
+			values:  map[string]string{"port": "http"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := newFlagSet()
+			ignored, err := applyConfig(fs, tt.values, tt.reload)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("bad error, expected error:%v ; actual:%v", tt.wantErr, err)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(ignored, tt.wantIgnored) {
+				t.Errorf("bad ignored flags, expected:%v ; actual:%v", tt.wantIgnored, ignored)
+			}
+			if region := fs.Lookup("region").Value.String(); region != tt.wantRegion {
+				t.Errorf("bad region, expected:%v ; actual:%v", tt.wantRegion, region)
+			}
+			if port := fs.Lookup("port").Value.String(); port != tt.wantPort {
+				t.Errorf("bad port, expected:%v ; actual:%v", tt.wantPort, port)
+			}
+		})
+	}
+}
+
+func TestSummaryPatterns(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		patterns string
+		want     []string
+	}{
+		{"pattern", "Congés", "", []string{"Congés"}},
+		{"patterns", "Congés", "RTT, Absence", []string{"RTT", "Absence"}},
+		{"empty", "", "", []string{""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := summaryPatterns(tt.pattern, tt.patterns); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("bad patterns, expected:%v ; actual:%v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseRegion(t *testing.T) {
+	for _, region := range calendar.Regions {
+		got, err := parseRegion(string(region))
+		if err != nil {
+			t.Errorf("unexpected error for region %v: %v", region, err)
+		}
+		if got != region {
+			t.Errorf("bad region, expected:%v ; actual:%v", region, got)
+		}
+	}
+	if _, err := parseRegion("guadeloupe"); err == nil {
+		t.Errorf("unknown region must fail")
+	}
+}
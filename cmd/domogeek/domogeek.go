@@ -3,128 +3,152 @@ package main
 import (
 	"context"
 	"domogeek/pkg/calendar"
-	"encoding/json"
+	"domogeek/pkg/params"
 	"flag"
 	"fmt"
 	"github.com/hellofresh/health-go/v4"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
 	"log"
+	"math"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"os/signal"
+	"reflect"
+	"strings"
 	"syscall"
 	"time"
+	// Embedded timezone database, used when the system one is missing such as on scratch images
+	_ "time/tzdata"
 )
 
-var (
-	cal          *calendar.Calendar
-	location     *time.Location
-	calCounter   *prometheus.CounterVec
-	calSummary   *prometheus.SummaryVec
-	calHistogram *prometheus.HistogramVec
+const (
+	shutdownTimeout = 10 * time.Second
+	// cacheFlushInterval is the delay between writes of the caldav cache file, to keep disk I/O off the requests
+	cacheFlushInterval = 10 * time.Second
+	socketMode         = 0o660
+	timeZone           = "Europe/Paris"
 )
 
-func init() {
-	loc, err := time.LoadLocation("Europe/Paris")
-	if err != nil {
-		zap.S().Fatalf("unable to load time location: %v", err)
-	}
-	location = loc
-
-	calCounter = promauto.NewCounterVec(prometheus.CounterOpts{
-		Namespace: "domogeek",
-		Subsystem: "calendar",
-		Name:      "request_total",
-		Help:      "Total request to calendar service",
-	},
-		[]string{
-			"code",
-			"method",
-		})
-
-	calSummary = promauto.NewSummaryVec(prometheus.SummaryOpts{
-		Namespace: "domogeek",
-		Subsystem: "calendar",
-		Name:      "summary",
-		Help:      "Calendar request summary",
-	},
-		nil)
-	calHistogram = promauto.NewHistogramVec(prometheus.HistogramOpts{
-		Namespace: "domogeek",
-		Subsystem: "calendar",
-		Name:      "histogram",
-		Help:      "Request duration histogram",
-	},
-		nil)
-}
-
-type CalendarDay struct {
-	Day        time.Time `json:"day"`
-	WorkingDay bool      `json:"working_day"`
-	Ferie      bool      `json:"ferie"`
-	Holiday    bool      `json:"holiday"`
-	Weekday    bool      `json:"weekday"`
-}
-
-type CalendarHandler struct{}
-
-func (c *CalendarHandler) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
-	now := time.Now()
-	calDavHolidays, err := cal.IsHolidaysFromCaldav(now)
-	if err != nil {
-		zap.S().Warnf("unable to read holiday status from caldav: %v", err)
-		calDavHolidays = false
-	}
-
-	cd := CalendarDay{
-		Day:        now,
-		WorkingDay: cal.IsWorkingDay(now),
-		Ferie:      cal.IsHoliday(now),
-		Holiday:    calDavHolidays,
-		Weekday:    cal.IsWeekDay(now),
-	}
-
-	content, err := json.Marshal(cd)
-	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		zap.S().Errorf("unable to marshall response %v, %v", content, err)
-	} else {
-		_, err = w.Write(content)
-		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			zap.S().Errorf("unable to marshall response %v, :%v", content, err)
-		}
-	}
-}
-
 func main() {
 	var port int
 	var host string
+	var unixSocket string
 	var user, pwd string
-	var caldavUrl, caldavPath, caldavSummaryPattern string
+	var caldavUrl, caldavPath, caldavSummaryPattern, caldavSummaryPatterns string
+	var logFormat string
+	var tlsCert, tlsKey string
+	var corsOrigin string
+	var caldavCacheTTL, cacheMaxAge, caldavTimeout, caldavWindowMargin time.Duration
+	var cacheFile string
+	var holidaysFile string
+	var configFile string
+	var region string
+	var debug bool
+	var fakeNow string
+	var bridgeDays bool
+	var nationalHolidays bool
+	var caldavAllDayOnly bool
+	var govHolidayAPI bool
+	var logHolidays bool
+	var caldavMaxEvents int
+	var caldavMaxIdleConns int
+	var caldavIdleConnTimeout time.Duration
+	var caldavDisableKeepAlives bool
+	var caldavQueryAttempts uint
+	var caldavCaseInsensitive bool
+	var caldavNormalize bool
+	var nextHolidayRefresh, requestTimeout time.Duration
+	var checkCaldavMode bool
+	var checkFrom, checkTo string
+	var weekStart string
+	var outputTimeZone string
+	var workingHours string
+	var halfDays string
+	var metricsEnabled bool
+	var metricsNamespace, metricsSubsystem, metricsPath string
 
 	flag.StringVar(&host, "host", "", "host to listen, default all addresses")
 	flag.IntVar(&port, "port", 8080, "port to listen")
+	flag.StringVar(&unixSocket, "unix-socket", "", "unix socket path to listen on instead of host and port")
 	flag.StringVar(&caldavUrl, "caldav-url", "", "caldav url to use to read holidays events")
 	flag.StringVar(&caldavPath, "caldav-path", "", "caldav path to use to read holidays events")
 	flag.StringVar(&caldavSummaryPattern, "caldav-summary-pattern", "Holidays", "Summary pattern that matches holidays event")
+	flag.StringVar(&caldavSummaryPatterns, "caldav-summary-patterns", "", "comma separated summary patterns, such as Congés,RTT, an event matching any of them is a holiday, replaces caldav-summary-pattern")
+	flag.DurationVar(&caldavCacheTTL, "caldav-cache-ttl", 0, "duration to keep caldav holiday status in cache, disabled if 0")
+	flag.StringVar(&cacheFile, "cache-file", "", "file to persist caldav holiday status, written periodically and on shutdown, used on restart when caldav is unavailable")
+	flag.DurationVar(&cacheMaxAge, "cache-max-age", 7*24*time.Hour, "max age of caldav holiday status read from cache-file")
+	flag.DurationVar(&caldavTimeout, "caldav-timeout", 0, "timeout of caldav requests, no timeout if 0")
+	flag.IntVar(&caldavMaxIdleConns, "caldav-max-idle-conns", 100, "max number of idle caldav connections kept open, no limit if 0")
+	flag.DurationVar(&caldavIdleConnTimeout, "caldav-idle-conn-timeout", 90*time.Second, "duration before closing idle caldav connections, no timeout if 0")
+	flag.BoolVar(&caldavDisableKeepAlives, "caldav-disable-keep-alives", false, "open a new connection for each caldav request")
+	flag.DurationVar(&caldavWindowMargin, "caldav-window-margin", 0, "margin added on both sides of the local day when querying caldav events")
+	flag.BoolVar(&caldavAllDayOnly, "caldav-all-day-only", false, "ignore timed caldav events, only all-day events are holidays")
+	flag.BoolVar(&caldavCaseInsensitive, "caldav-case-insensitive", false, "ignore case when matching caldav event summaries with caldav-summary-pattern")
+	flag.BoolVar(&caldavNormalize, "caldav-normalize", false, "trim and remove accents of caldav event summaries and caldav-summary-pattern before matching them")
+	flag.IntVar(&caldavMaxEvents, "caldav-max-events", 1000, "max number of events scanned by caldav query, no limit if 0")
+	flag.UintVar(&caldavQueryAttempts, "caldav-query-attempts", 1, "number of attempts of each caldav query, with a short backoff, on transient errors")
+	flag.BoolVar(&checkCaldavMode, "check-caldav", false, "check caldav configuration, print matching events between check-from and check-to then exit")
+	flag.StringVar(&checkFrom, "check-from", "", "first day checked by check-caldav, YYYY-MM-DD, today by default")
+	flag.StringVar(&checkTo, "check-to", "", "last day checked by check-caldav, YYYY-MM-DD, 30 days after check-from by default")
 	flag.StringVar(&user, "caldav-username", "", "Username credential")
 	flag.StringVar(&pwd, "caldav-password", "", "Password credential")
-	flag.Parse()
+	flag.StringVar(&tlsCert, "tls-cert", "", "TLS certificate file, serve HTTPS when set with tls-key")
+	flag.StringVar(&tlsKey, "tls-key", "", "TLS private key file, serve HTTPS when set with tls-cert")
+	flag.StringVar(&corsOrigin, "cors-origin", "", "origins allowed by CORS, comma separated or * for any origin, disabled if empty")
+	flag.DurationVar(&requestTimeout, "request-timeout", 30*time.Second, "max duration to handle a request, replied 503 when exceeded, no timeout if 0")
+	flag.DurationVar(&nextHolidayRefresh, "next-holiday-refresh", time.Hour, "refresh interval of the days until next holiday metric, only computed at startup if 0")
+	flag.StringVar(&holidaysFile, "holidays-file", "", "JSON file of additional holidays, [{\"date\": \"YYYY-MM-DD\" or \"MM-DD\" for every year, \"name\": \"...\"}]")
+	flag.StringVar(&configFile, "config-file", "", "file of flags, a name=value line per flag, read at startup and on SIGHUP for holidays-file, caldav-summary-pattern(s) and region")
+	flag.StringVar(&region, "region", string(calendar.RegionMetropole), "region of the routes without region, such as /calendar, metropole, alsace-moselle or corse")
+	flag.BoolVar(&govHolidayAPI, "gov-holiday-api", false, "use the official holidays API calendrier.api.gouv.fr as authoritative source of national holidays")
+	flag.BoolVar(&nationalHolidays, "national-holidays", true, "report national holidays, disable to only rely on caldav and holidays-file")
+	flag.BoolVar(&bridgeDays, "bridge-days", false, "report bridge days (ponts) as non-working days")
+	flag.StringVar(&fakeNow, "fake-now", "", "RFC3339 time to use instead of the current time, for testing purpose")
+	flag.BoolVar(&metricsEnabled, "metrics", true, "expose prometheus metrics on metrics-path")
+	flag.StringVar(&metricsPath, "metrics-path", "/metrics", "path of prometheus metrics, disabled if empty")
+	flag.StringVar(&metricsNamespace, "metrics-namespace", "domogeek", "namespace of prometheus metrics")
+	flag.StringVar(&metricsSubsystem, "metrics-subsystem", "calendar", "subsystem of prometheus metrics")
+	flag.StringVar(&workingHours, "working-hours", "", "working hours of working days reported by /calendar, such as 09:00-18:00, the whole day by default")
+	flag.StringVar(&halfDays, "half-days", "", "comma separated MM-DD days whose afternoon is off, such as 12-24,12-31")
+	flag.StringVar(&outputTimeZone, "output-timezone", "", "timezone of the days in calendar responses, such as UTC, the computation timezone "+timeZone+" by default")
+	flag.StringVar(&weekStart, "week-start", "monday", "first day of the weeks returned by /calendar/week, monday or sunday")
+	flag.BoolVar(&logHolidays, "log-holidays", false, "log holidays of the current year at startup")
+	flag.BoolVar(&debug, "debug", false, "expose debug endpoints under /debug/")
+	flag.StringVar(&logFormat, "log-format", "console", "log format, console or json")
 
 	logLevel := zap.LevelFlag("log", zap.InfoLevel, "log level")
+	accessLogLevel := zap.LevelFlag("access-log-level", zap.InfoLevel, "log level of access logs")
+	trustProxy := flag.Bool("trust-proxy", false, "log the client IP from X-Forwarded-For or X-Real-IP headers, only behind a reverse proxy setting them")
 	flag.Parse()
 
 	if len(os.Args) <= 1 {
 		flag.PrintDefaults()
 		os.Exit(1)
 	}
+	if configFile != "" {
+		values, err := readConfigFile(configFile)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		if _, err := applyConfig(flag.CommandLine, values, false); err != nil {
+			log.Fatalf("%v", err)
+		}
+	}
 
-	config := zap.NewDevelopmentConfig()
+	var config zap.Config
+	switch logFormat {
+	case "console":
+		config = zap.NewDevelopmentConfig()
+	case "json":
+		config = zap.NewProductionConfig()
+	default:
+		log.Fatalf("invalid log format '%v', expected console or json", logFormat)
+	}
 	config.Level = zap.NewAtomicLevelAt(*logLevel)
 	lgr, err := config.Build()
 	if err != nil {
@@ -135,48 +159,254 @@ func main() {
 	}()
 	zap.ReplaceGlobals(lgr)
 
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		zap.S().Fatalf("unable to load time location %v, check the ZONEINFO environment variable or the tzdata of the system: %v", timeZone, err)
+	}
+	output := location
+	if outputTimeZone != "" {
+		output, err = time.LoadLocation(outputTimeZone)
+		if err != nil {
+			zap.S().Fatalf("invalid output-timezone '%v': %v", outputTimeZone, err)
+		}
+	}
+
+	if (tlsCert == "") != (tlsKey == "") {
+		zap.S().Fatalf("both tls-cert and tls-key flags must be set to enable TLS")
+	}
+	caldavPatterns := summaryPatterns(caldavSummaryPattern, caldavSummaryPatterns)
+	if caldavSummaryPatterns == "" && caldavSummaryPattern == "" {
+		zap.S().Warnf("empty caldav-summary-pattern, no caldav event is a holiday")
+	}
+	defaultRegion, err := parseRegion(region)
+	if err != nil {
+		zap.S().Fatalf("invalid region: %v", err)
+	}
+	if weekStart != "monday" && weekStart != "sunday" {
+		zap.S().Fatalf("invalid week-start '%v', monday or sunday expected", weekStart)
+	}
+
+	clock := time.Now
+	if fakeNow != "" {
+		c, err := fakeClock(fakeNow)
+		if err != nil {
+			zap.S().Fatalf("%v", err)
+		}
+		zap.S().Warnf("use fake current time %v", c())
+		clock = c
+	}
+
 	urlCaldav, err := url.Parse(caldavUrl)
 	if err != nil {
 		zap.S().Panicf("invalid caldav url '%v': %v", caldavUrl, err)
 	}
 	urlCaldav.User = url.UserPassword(user, pwd)
 
-	cdav, err := calendar.NewCaldav(urlCaldav.String(), caldavPath)
+	// Disabled metrics are still collected, in a registry that is not exposed
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	m := newMetrics(registry, metricsNamespace, metricsSubsystem)
+
+	caldavOptions := []calendar.CaldavOption{
+		calendar.WithHTTPClient(&http.Client{Timeout: caldavTimeout}),
+		calendar.WithMaxIdleConns(caldavMaxIdleConns),
+		calendar.WithIdleConnTimeout(caldavIdleConnTimeout),
+		calendar.WithDisableKeepAlives(caldavDisableKeepAlives),
+		calendar.WithCaldavLogger(zap.S()),
+		calendar.WithRateLimitHook(func(time.Time) {
+			m.rateLimited.Inc()
+		}),
+	}
+	if checkCaldavMode {
+		caldavOptions = append(caldavOptions, calendar.WithValidateAttempts(1))
+	}
+	cdav, err := calendar.NewCaldav(urlCaldav.String(), caldavPath, caldavOptions...)
 	if err != nil {
+		if checkCaldavMode {
+			fmt.Printf("KO: %v\n", err)
+			os.Exit(1)
+		}
 		zap.S().Fatal("unable to init caldav instance")
 	}
-	cal = calendar.New(location,
+	caldavCache := calendar.NewCaldavCache(caldavCacheTTL)
+	if cacheFile != "" && !checkCaldavMode {
+		if err := caldavCache.Persist(cacheFile, cacheMaxAge); err != nil {
+			zap.S().Warnf("unable to load caldav cache: %v", err)
+		}
+	}
+	calendarOptions := []calendar.Option{
 		calendar.WithCaldav(cdav),
 		calendar.WithCaldavPath(caldavPath),
-		calendar.WithCaldavSummaryPattern(caldavSummaryPattern),
-	)
+		calendar.WithCaldavSummaryPatterns(caldavPatterns),
+		calendar.WithCaldavCache(caldavCache),
+		calendar.WithCaldavWindowMargin(caldavWindowMargin),
+		calendar.WithCaldavAllDayOnly(caldavAllDayOnly),
+		calendar.WithCaldavMaxEvents(caldavMaxEvents),
+		calendar.WithCaldavQueryAttempts(caldavQueryAttempts),
+		calendar.WithCaldavCaseInsensitive(caldavCaseInsensitive),
+		calendar.WithCaldavNormalize(caldavNormalize),
+		calendar.WithNationalHolidays(nationalHolidays),
+		calendar.WithBridgeDays(bridgeDays),
+		calendar.WithGovHolidayAPI(govHolidayAPI),
+		calendar.WithLogger(zap.S()),
+		calendar.WithClock(clock),
+	}
+	if workingHours != "" {
+		hours, err := calendar.ParseWorkingHours(workingHours)
+		if err != nil {
+			zap.S().Fatalf("invalid working-hours: %v", err)
+		}
+		calendarOptions = append(calendarOptions, calendar.WithWorkingHours(hours))
+	}
+	if halfDays != "" {
+		days, err := calendar.ParseHalfDays(halfDays)
+		if err != nil {
+			zap.S().Fatalf("invalid half-days: %v", err)
+		}
+		calendarOptions = append(calendarOptions, calendar.WithHalfDays(days...))
+	}
+	envHolidays, err := extraHolidays(os.Getenv(extraHolidaysEnv))
+	if err != nil {
+		zap.S().Fatalf("unable to load holidays: %v", err)
+	}
+	if len(envHolidays) > 0 {
+		calendarOptions = append(calendarOptions, calendar.WithCustomHolidays(envHolidays))
+	}
+	regionCalendars, err := newRegionCalendars(location, calendarOptions, holidaysFile)
+	if err != nil {
+		zap.S().Fatalf("unable to load holidays: %v", err)
+	}
+	cal := regionCalendars[defaultRegion]
+
+	if checkCaldavMode {
+		if !checkCaldav(cal, clock(), checkFrom, checkTo) {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	if logHolidays {
+		for _, region := range calendar.Regions {
+			logYearHolidays(regionCalendars[region], region, clock().In(location).Year())
+		}
+	}
 
-	addr := fmt.Sprintf("%s:%d", host, port)
-	zap.S().Infof("start server on %s", addr)
-
-	h := promhttp.InstrumentHandlerDuration(
-		calHistogram,
-		promhttp.InstrumentHandlerDuration(
-			calSummary,
-			promhttp.InstrumentHandlerCounter(
-				calCounter,
-				&CalendarHandler{})))
-	http.Handle("/calendar", &h)
-	http.Handle("/metrics", promhttp.Handler())
-	healthz, _ := health.New(health.WithChecks(health.Config{
+	holders := make(map[calendar.Region]*calendarHolder, len(regionCalendars))
+	for region, c := range regionCalendars {
+		holders[region] = newCalendarHolder(c)
+	}
+	regions := &regionHolder{region: defaultRegion}
+	// holder follows the region changes of configuration reloads
+	holder := &defaultCalendar{holders: holders, region: regions}
+
+	middlewares := []middleware{accessLog(*accessLogLevel, *trustProxy)}
+	if corsOrigin != "" {
+		middlewares = append(middlewares, cors(corsOrigin))
+	}
+	if requestTimeout > 0 {
+		middlewares = append(middlewares, timeout(requestTimeout))
+	}
+	middlewares = append(middlewares, allowMethods(http.MethodGet, http.MethodHead), head)
+	route := func(name string, region calendar.Region, handler http.Handler) http.Handler {
+		return m.route(name, region, compress(handler), middlewares...)
+	}
+	// regional routes the requests of a route without region to the handler of the -region calendar
+	regional := func(name string, newHandler func(h *calendarHolder) http.Handler) http.Handler {
+		handlers := make(map[calendar.Region]http.Handler, len(holders))
+		for region, h := range holders {
+			handlers[region] = route(name, region, newHandler(h))
+		}
+		return &DefaultRegionRouter{region: regions, handlers: handlers}
+	}
+	// patterns are the registered routes, the metrics path must not collide with them
+	var patterns []string
+	handle := func(pattern string, handler http.Handler) {
+		patterns = append(patterns, pattern)
+		http.Handle(pattern, handler)
+	}
+	handle("/calendar", regional("/calendar", func(h *calendarHolder) http.Handler {
+		return &CalendarHandler{cal: h, output: output}
+	}))
+	regionRouter := &RegionRouter{
+		prefix:   "/calendar/",
+		handlers: make(map[string]http.Handler, len(holders)),
+		dates: regional("/calendar/{date}", func(h *calendarHolder) http.Handler {
+			return &CalendarDateHandler{cal: h, output: output, prefix: "/calendar/"}
+		}),
+	}
+	for region, h := range holders {
+		regionRouter.handlers[string(region)] = route("/calendar/{region}", region, &CalendarHandler{cal: h, output: output})
+	}
+	handle("/calendar/", regionRouter)
+	handle("/working-today", regional("/working-today", func(h *calendarHolder) http.Handler {
+		return &WorkingTodayHandler{cal: h}
+	}))
+	handle("/calendar/week", regional("/calendar/week", func(h *calendarHolder) http.Handler {
+		return &CalendarWeekHandler{cal: h, output: output, sundayFirst: weekStart == "sunday"}
+	}))
+	handle("/calendar/range", regional("/calendar/range", func(h *calendarHolder) http.Handler {
+		return &CalendarRangeHandler{cal: h, output: output}
+	}))
+	handle("/is-holiday", regional("/is-holiday", func(h *calendarHolder) http.Handler {
+		return &IsHolidayHandler{cal: h}
+	}))
+	handle("/is-bridge", regional("/is-bridge", func(h *calendarHolder) http.Handler {
+		return &IsBridgeHandler{cal: h}
+	}))
+	handle("/holidays", regional("/holidays", func(h *calendarHolder) http.Handler {
+		return &HolidaysHandler{cal: h}
+	}))
+	handle("/holidays.ics", regional("/holidays.ics", func(h *calendarHolder) http.Handler {
+		return &HolidaysICSHandler{cal: h}
+	}))
+	handle("/holidays/count", regional("/holidays/count", func(h *calendarHolder) http.Handler {
+		return &HolidayCountHandler{cal: h}
+	}))
+	handle("/stats", regional("/stats", func(h *calendarHolder) http.Handler {
+		return &StatsHandler{cal: h}
+	}))
+	handle("/holidays/upcoming", regional("/holidays/upcoming", func(h *calendarHolder) http.Handler {
+		return &UpcomingHolidaysHandler{cal: h}
+	}))
+	handle("/holidays/month", regional("/holidays/month", func(h *calendarHolder) http.Handler {
+		return &HolidaysOfMonthHandler{cal: h}
+	}))
+	handle("/workingdays/month", regional("/workingdays/month", func(h *calendarHolder) http.Handler {
+		return &WorkingDaysInMonthHandler{cal: h}
+	}))
+	handle("/workingdays/list", regional("/workingdays/list", func(h *calendarHolder) http.Handler {
+		return &WorkingDaysHandler{cal: h}
+	}))
+	handle("/version", route("/version", calendar.RegionMetropole, &VersionHandler{}))
+	if debug {
+		handle("/debug/cache", &CacheStatsHandler{cal: holder})
+	}
+	calendarCheck := health.WithChecks(health.Config{
 		Name:      "calendar",
 		Timeout:   time.Second * 5,
 		SkipOnErr: false,
 		Check: func(ctx context.Context) error {
 			return nil
 		},
-	}),
+	})
+	// Liveness doesn't depend on caldav, an unavailable caldav server must not restart the service
+	livez, _ := health.New(calendarCheck)
+	handle("/healthz", livez.Handler())
+	// Readiness waits for a first successful CalDAV query, so that the first requests don't populate the cache
+	ready := &readiness{}
+	healthz, _ := health.New(calendarCheck,
 		health.WithChecks(health.Config{
+			Name:      "warmup",
+			Timeout:   time.Second,
+			SkipOnErr: false,
+			Check:     ready.check,
+		}, health.Config{
 			Name:      "caldav",
 			Timeout:   5 * time.Second,
 			SkipOnErr: false,
 			Check: func(ctx context.Context) error {
-				_, err := cal.IsHolidaysFromCaldav(time.Now())
+				cal := holder.Load()
+				_, err := cal.IsHolidaysFromCaldav(cal.Now())
 				if err != nil {
 					zap.S().Warnf("unable to check caldav connection: %v", err)
 				}
@@ -184,14 +414,245 @@ func main() {
 			},
 		}),
 	)
-	http.Handle("/status", healthz.Handler())
+	handle("/status", healthz.Handler())
+	if metricsEnabled && metricsPath != "" {
+		if err := validateMetricsPath(metricsPath, patterns); err != nil {
+			zap.S().Fatalf("invalid metrics-path: %v", err)
+		}
+		http.Handle(metricsPath, promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	}
+
+	refreshCtx, stopRefresh := context.WithCancel(context.Background())
+	defer stopRefresh()
+	go refreshNextHoliday(refreshCtx, holder, nextHolidayRefresh, m.nextHoliday)
+	go warmUp(refreshCtx, holder, warmUpRetry, ready)
+	go flushCaldavCache(refreshCtx, caldavCache, cacheFlushInterval)
 
+	var listener net.Listener
+	if unixSocket != "" {
+		listener, err = listenUnix(unixSocket)
+	} else {
+		listener, err = net.Listen("tcp", fmt.Sprintf("%s:%d", host, port))
+	}
+	if err != nil {
+		zap.S().Fatalf("unable to listen: %v", err)
+	}
+	zap.S().Infof("start server %v (commit %v, built %v) on %s", version, commit, buildDate, listener.Addr())
+
+	server := &http.Server{}
 	signChan := make(chan os.Signal, 1)
 	go func() {
-		zap.S().Fatal(http.ListenAndServe(addr, nil))
+		var err error
+		if tlsCert != "" {
+			err = server.ServeTLS(listener, tlsCert, tlsKey)
+		} else {
+			err = server.Serve(listener)
+		}
+		if err != nil && err != http.ErrServerClosed {
+			zap.S().Fatal(err)
+		}
 	}()
 
-	signal.Notify(signChan, syscall.SIGTERM)
-	<-signChan
+	signal.Notify(signChan, syscall.SIGTERM, syscall.SIGHUP)
+	for sig := range signChan {
+		if sig != syscall.SIGHUP {
+			break
+		}
+		if configFile != "" {
+			values, err := readConfigFile(configFile)
+			if err != nil {
+				zap.S().Errorf("unable to reload configuration, keep the current one: %v", err)
+				continue
+			}
+			ignored, err := applyConfig(flag.CommandLine, values, true)
+			if err != nil {
+				zap.S().Errorf("unable to reload configuration, keep the current one: %v", err)
+				continue
+			}
+			for _, name := range ignored {
+				zap.S().Warnf("flag '%v' of config file can't be reloaded, ignored until restart", name)
+			}
+		}
+		newRegion, err := parseRegion(region)
+		if err != nil {
+			zap.S().Errorf("unable to reload configuration, keep the current one: %v", err)
+			continue
+		}
+		newPatterns := summaryPatterns(caldavSummaryPattern, caldavSummaryPatterns)
+		options := append(calendarOptions[:len(calendarOptions):len(calendarOptions)], calendar.WithCaldavSummaryPatterns(newPatterns))
+		if err := reloadCalendars(holders, location, options, holidaysFile); err != nil {
+			zap.S().Errorf("unable to reload configuration, keep the current one: %v", err)
+			continue
+		}
+		regions.Store(newRegion)
+		if !reflect.DeepEqual(newPatterns, caldavPatterns) {
+			// cached caldav status was computed with the previous patterns
+			caldavCache.Clear()
+			caldavPatterns = newPatterns
+		}
+		if holidaysFile != "" {
+			zap.S().Infof("holidays reloaded from file '%v'", holidaysFile)
+		}
+		zap.S().Infof("configuration reloaded, region %v, caldav summary patterns %v", newRegion, newPatterns)
+	}
 	zap.S().Info("exit on sigterm")
+	stopRefresh()
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		zap.S().Errorf("unable to shutdown server gracefully: %v", err)
+	}
+	// after the shutdown, to keep the updates of the last requests
+	if err := caldavCache.Flush(); err != nil {
+		zap.S().Errorf("unable to write caldav cache: %v", err)
+	}
+	if unixSocket != "" {
+		if err := os.Remove(unixSocket); err != nil && !os.IsNotExist(err) {
+			zap.S().Errorf("unable to remove unix socket: %v", err)
+		}
+	}
+}
+
+// extraHolidaysEnv is the environment variable of additional holidays, a JSON array in the format of the holidays
+// file, handy for test environments without a file
+const extraHolidaysEnv = "DOMOGEEK_EXTRA_HOLIDAYS"
+
+// fakeClock returns a clock always returning value, an RFC3339 time, for the -fake-now flag
+func fakeClock(value string) (func() time.Time, error) {
+	now, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fake-now '%v', RFC3339 format expected: %w", value, err)
+	}
+	return func() time.Time {
+		return now
+	}, nil
+}
+
+// extraHolidays decodes value, the content of extraHolidaysEnv, no holidays if empty
+func extraHolidays(value string) ([]calendar.CustomHoliday, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	holidays, err := calendar.ParseCustomHolidays([]byte(value))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %v: %w", extraHolidaysEnv, err)
+	}
+	return holidays, nil
+}
+
+// newRegionCalendars returns the calendar of each region, with the holidays of holidaysFile if set
+func newRegionCalendars(location *time.Location, options []calendar.Option, holidaysFile string) (map[calendar.Region]*calendar.Calendar, error) {
+	if holidaysFile != "" {
+		customHolidays, err := calendar.LoadCustomHolidays(holidaysFile)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options[:len(options):len(options)], calendar.WithCustomHolidays(customHolidays))
+	}
+	regionCalendars := make(map[calendar.Region]*calendar.Calendar, len(calendar.Regions))
+	for _, region := range calendar.Regions {
+		regionCalendars[region] = calendar.New(location, append(options[:len(options):len(options)], calendar.WithRegion(region))...)
+	}
+	return regionCalendars, nil
+}
+
+// reloadCalendars builds new calendars, with the current content of holidaysFile, and swaps them in holders. Holders
+// are left untouched on error.
+func reloadCalendars(holders map[calendar.Region]*calendarHolder, location *time.Location, options []calendar.Option, holidaysFile string) error {
+	regionCalendars, err := newRegionCalendars(location, options, holidaysFile)
+	if err != nil {
+		return err
+	}
+	for region, h := range holders {
+		h.Store(regionCalendars[region])
+	}
+	return nil
+}
+
+// logYearHolidays logs the holidays of the year in a single line, to check the configuration at startup
+func logYearHolidays(cal *calendar.Calendar, region calendar.Region, year int) {
+	holidays := cal.GetHolidaysNamed(year)
+	days := make([]string, 0, len(holidays))
+	for _, h := range holidays {
+		days = append(days, fmt.Sprintf("%v %v", h.Date.Format(params.DateLayout), h.Name))
+	}
+	zap.S().Infof("%d holidays in %d for region %v (%v): %v", len(holidays), year, region, cal.Location, strings.Join(days, ", "))
+}
+
+// listenUnix listens on the unix socket path, readable and writable by the group. A stale socket left by a previous
+// run is removed, any other file at path is an error.
+func listenUnix(path string) (net.Listener, error) {
+	info, err := os.Lstat(path)
+	if err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("'%v' exists and is not a socket", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("unable to remove stale socket '%v': %w", path, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("unable to stat socket '%v': %w", path, err)
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, socketMode); err != nil {
+		_ = listener.Close()
+		return nil, fmt.Errorf("unable to set socket '%v' permissions: %w", path, err)
+	}
+	return listener, nil
+}
+
+// refreshNextHoliday updates the days until next holiday gauge every interval, until ctx is done. The gauge is only
+// updated once if interval isn't positive.
+func refreshNextHoliday(ctx context.Context, holder calendarLoader, interval time.Duration, nextHoliday prometheus.Gauge) {
+	updateNextHoliday(holder.Load(), nextHoliday)
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		updateNextHoliday(holder.Load(), nextHoliday)
+	}
+}
+
+// flushCaldavCache writes the caldav cache file every interval until ctx is done, Flush being a no-op when the cache
+// isn't persisted or didn't change
+func flushCaldavCache(ctx context.Context, cache *calendar.CaldavCache, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := cache.Flush(); err != nil {
+			zap.S().Warnf("unable to write caldav cache: %v", err)
+		}
+	}
+}
+
+// updateNextHoliday sets the days until next holiday gauge
+func updateNextHoliday(cal *calendar.Calendar, nextHoliday prometheus.Gauge) {
+	now := cal.Now().In(cal.Location)
+	date, name := cal.NextHoliday(now)
+	if date.IsZero() {
+		zap.S().Warnf("no holiday found after %v", now)
+		return
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, cal.Location)
+	days := math.Round(date.Sub(today).Hours() / 24)
+	zap.S().Debugf("next holiday '%v' on %v, in %v days", name, date, days)
+	nextHoliday.Set(days)
 }
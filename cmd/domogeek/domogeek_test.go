@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"domogeek/pkg/calendar"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestListenUnix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "domogeek.sock")
+
+	// Stale socket, not removed on close
+	stale, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("unable to create stale socket: %v", err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	_ = stale.Close()
+
+	listener, err := listenUnix(path)
+	if err != nil {
+		t.Fatalf("stale socket should be replaced: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("unable to stat socket: %v", err)
+	}
+	if info.Mode().Perm() != socketMode {
+		t.Errorf("bad socket permissions, expected:%v ; actual:%v", os.FileMode(socketMode), info.Mode().Perm())
+	}
+	_ = listener.Close()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("socket should be removed on close: %v", err)
+	}
+
+	regular := filepath.Join(t.TempDir(), "regular")
+	if err := os.WriteFile(regular, []byte("data"), 0o600); err != nil {
+		t.Fatalf("unable to write file: %v", err)
+	}
+	if _, err := listenUnix(regular); err == nil {
+		t.Error("regular file should not be replaced by a socket")
+	}
+}
+
+func TestReloadCalendars(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatalf("unable to load time location: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "holidays.json")
+	if err := os.WriteFile(path, []byte(`[{"date": "03-17", "name": "Anniversaire de la société"}]`), 0o600); err != nil {
+		t.Fatalf("unable to write holidays file: %v", err)
+	}
+	regionCalendars, err := newRegionCalendars(loc, nil, path)
+	if err != nil {
+		t.Fatalf("unable to build calendars: %v", err)
+	}
+	holders := make(map[calendar.Region]*calendarHolder, len(regionCalendars))
+	for region, cal := range regionCalendars {
+		holders[region] = newCalendarHolder(cal)
+	}
+
+	day := time.Date(2024, time.March, 18, 0, 0, 0, 0, loc)
+	if holders[calendar.RegionMetropole].Load().IsHoliday(day) {
+		t.Errorf("%v should not be a holiday before reload", day)
+	}
+
+	if err := os.WriteFile(path, []byte(`[{"date": "03-18", "name": "Anniversaire de la société"}]`), 0o600); err != nil {
+		t.Fatalf("unable to write holidays file: %v", err)
+	}
+	if err := reloadCalendars(holders, loc, nil, path); err != nil {
+		t.Fatalf("unable to reload calendars: %v", err)
+	}
+	for _, region := range calendar.Regions {
+		if !holders[region].Load().IsHoliday(day) {
+			t.Errorf("%v should be a holiday after reload for region %v", day, region)
+		}
+	}
+
+	current := holders[calendar.RegionMetropole].Load()
+	if err := os.WriteFile(path, []byte(`[{"date": "17/03"}]`), 0o600); err != nil {
+		t.Fatalf("unable to write holidays file: %v", err)
+	}
+	if err := reloadCalendars(holders, loc, nil, path); err == nil {
+		t.Error("invalid holidays file should not be reloaded")
+	}
+	if holders[calendar.RegionMetropole].Load() != current {
+		t.Error("calendar should be kept on reload error")
+	}
+}
+
+func TestExtraHolidays(t *testing.T) {
+	holidays, err := extraHolidays(`[{"date": "2024-03-18", "name": "Séminaire"}, {"date": "12-24", "name": "Réveillon"}]`)
+	if err != nil {
+		t.Fatalf("unable to parse holidays: %v", err)
+	}
+	want := []calendar.CustomHoliday{
+		{Year: 2024, Month: time.March, Day: 18, Name: "Séminaire"},
+		{Month: time.December, Day: 24, Name: "Réveillon"},
+	}
+	if len(holidays) != len(want) {
+		t.Fatalf("bad holidays count, expected:%v ; actual:%v", len(want), len(holidays))
+	}
+	for i := range want {
+		if holidays[i] != want[i] {
+			t.Errorf("bad holiday, expected:%v ; actual:%v", want[i], holidays[i])
+		}
+	}
+
+	if holidays, err := extraHolidays(""); err != nil || holidays != nil {
+		t.Errorf("empty value should have no holidays: %v, %v", holidays, err)
+	}
+	for _, value := range []string{`{"date": "12-24"}`, `[{"date": "24/12", "name": "Réveillon"}]`, `[{"date": "12-24"}]`} {
+		if _, err := extraHolidays(value); err == nil || !strings.Contains(err.Error(), extraHolidaysEnv) {
+			t.Errorf("invalid value %v should fail with the variable name: %v", value, err)
+		}
+	}
+}
+
+func TestFakeClock(t *testing.T) {
+	if _, err := fakeClock("2024-12-24"); err == nil {
+		t.Error("a time without clock should be rejected")
+	}
+
+	// Tuesday 24 December 2024, the next holiday is Christmas
+	clock, err := fakeClock("2024-12-24T10:00:00+01:00")
+	if err != nil {
+		t.Fatalf("unable to parse fake now: %v", err)
+	}
+	cal := newTestCalendar(t, calendar.WithClock(clock))
+	holder := newCalendarHolder(cal)
+
+	w := httptest.NewRecorder()
+	(&WorkingTodayHandler{cal: holder}).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/working-today", nil))
+	if body := w.Body.String(); body != "true" {
+		t.Errorf("bad working today, expected:true ; actual:%v", body)
+	}
+
+	w = httptest.NewRecorder()
+	(&UpcomingHolidaysHandler{cal: holder}).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/holidays/upcoming?n=1", nil))
+	var holidays []calendar.Holiday
+	if err := json.Unmarshal(w.Body.Bytes(), &holidays); err != nil {
+		t.Fatalf("unable to unmarshal response: %v", err)
+	}
+	if len(holidays) != 1 || holidays[0].Name != "Noël" {
+		t.Errorf("bad next holiday, expected:Noël ; actual:%v", holidays)
+	}
+
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "next_holiday_days"})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	refreshNextHoliday(ctx, holder, time.Hour, gauge)
+	if got := testutil.ToFloat64(gauge); got != 1 {
+		t.Errorf("bad days until next holiday, expected:1 ; actual:%v", got)
+	}
+}
+
+func TestRefreshNextHoliday_Disabled(t *testing.T) {
+	clock, err := fakeClock("2024-12-24T10:00:00+01:00")
+	if err != nil {
+		t.Fatalf("unable to parse fake now: %v", err)
+	}
+	holder := newCalendarHolder(newTestCalendar(t, calendar.WithClock(clock)))
+	for _, interval := range []time.Duration{0, -time.Second} {
+		gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "next_holiday_days"})
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			refreshNextHoliday(context.Background(), holder, interval, gauge)
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatalf("refresh should return at once with interval %v", interval)
+		}
+		if got := testutil.ToFloat64(gauge); got != 1 {
+			t.Errorf("bad days until next holiday with interval %v, expected:1 ; actual:%v", interval, got)
+		}
+	}
+}
+
+func TestRefreshNextHoliday_DefaultRegion(t *testing.T) {
+	clock, err := fakeClock("2024-03-28T10:00:00+01:00")
+	if err != nil {
+		t.Fatalf("unable to parse fake now: %v", err)
+	}
+	holders := map[calendar.Region]*calendarHolder{
+		calendar.RegionMetropole:     newCalendarHolder(newTestCalendar(t, calendar.WithClock(clock))),
+		calendar.RegionAlsaceMoselle: newCalendarHolder(newTestCalendar(t, calendar.WithClock(clock), calendar.WithRegion(calendar.RegionAlsaceMoselle))),
+	}
+	regions := &regionHolder{region: calendar.RegionMetropole}
+	holder := &defaultCalendar{holders: holders, region: regions}
+
+	// Good Friday is on 29 March 2024 in Alsace-Moselle, Easter Monday on 1 April everywhere
+	tests := []struct {
+		region calendar.Region
+		want   float64
+	}{
+		{calendar.RegionMetropole, 4},
+		{calendar.RegionAlsaceMoselle, 1},
+	}
+	for _, tt := range tests {
+		regions.Store(tt.region)
+		gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "next_holiday_days"})
+		refreshNextHoliday(context.Background(), holder, 0, gauge)
+		if got := testutil.ToFloat64(gauge); got != tt.want {
+			t.Errorf("bad days until next holiday in %v, expected:%v ; actual:%v", tt.region, tt.want, got)
+		}
+	}
+}
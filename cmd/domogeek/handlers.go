@@ -0,0 +1,758 @@
+package main
+
+import (
+	"domogeek/pkg/calendar"
+	"domogeek/pkg/params"
+	"encoding/json"
+	"fmt"
+	"go.uber.org/zap"
+	"hash/fnv"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CalendarDay is the calendar status of a day in version 1 responses, the default, kept for existing clients.
+// CaldavEvent is the CalDAV event making the day a holiday, if any. WorkingHoursNow is only set for the current day.
+type CalendarDay struct {
+	Day             time.Time             `json:"day"`
+	WorkingDay      bool                  `json:"working_day"`
+	Ferie           bool                  `json:"ferie"`
+	Holiday         bool                  `json:"holiday"`
+	Weekday         bool                  `json:"weekday"`
+	Bridge          bool                  `json:"bridge"`
+	Name            string                `json:"name,omitempty"`
+	Source          string                `json:"source,omitempty"`
+	WorkingHoursNow *bool                 `json:"working_hours_now,omitempty"`
+	DayType         string                `json:"day_type"`
+	CaldavEvent     *calendar.CaldavEvent `json:"caldav_event,omitempty"`
+}
+
+// CalendarDayV2 is the calendar status of a day in version 2 responses, requested with v=2, with English field
+// names. IsHoliday is a public holiday, Ferie of version 1, and IsVacation a CalDAV holiday, Holiday of version 1.
+type CalendarDayV2 struct {
+	Day            time.Time             `json:"day"`
+	IsWorkingDay   bool                  `json:"is_working_day"`
+	IsHoliday      bool                  `json:"is_holiday"`
+	IsVacation     bool                  `json:"is_vacation"`
+	IsWeekday      bool                  `json:"is_weekday"`
+	IsBridge       bool                  `json:"is_bridge"`
+	HolidayName    string                `json:"holiday_name,omitempty"`
+	HolidaySource  string                `json:"holiday_source,omitempty"`
+	IsWorkingHours *bool                 `json:"is_working_hours,omitempty"`
+	DayType        string                `json:"day_type"`
+	CaldavEvent    *calendar.CaldavEvent `json:"caldav_event,omitempty"`
+}
+
+// V2 returns the version 2 response of cd
+func (cd CalendarDay) V2() CalendarDayV2 {
+	return CalendarDayV2{
+		Day:            cd.Day,
+		IsWorkingDay:   cd.WorkingDay,
+		IsHoliday:      cd.Ferie,
+		IsVacation:     cd.Holiday,
+		IsWeekday:      cd.Weekday,
+		IsBridge:       cd.Bridge,
+		HolidayName:    cd.Name,
+		HolidaySource:  cd.Source,
+		IsWorkingHours: cd.WorkingHoursNow,
+		DayType:        cd.DayType,
+		CaldavEvent:    cd.CaldavEvent,
+	}
+}
+
+// Versions of calendar responses, selected by the v parameter
+const (
+	responseV1 = 1
+	responseV2 = 2
+)
+
+// calendarDayResponse returns cd shaped as version
+func calendarDayResponse(version int, cd CalendarDay) interface{} {
+	if version == responseV2 {
+		return cd.V2()
+	}
+	return cd
+}
+
+// calendarDaysResponse returns days shaped as version
+func calendarDaysResponse(version int, days []CalendarDay) interface{} {
+	if version != responseV2 {
+		return days
+	}
+	v2 := make([]CalendarDayV2, 0, len(days))
+	for _, cd := range days {
+		v2 = append(v2, cd.V2())
+	}
+	return v2
+}
+
+// calendarRequest parses the language, see requestLanguage, and the response version, the v parameter, common to
+// calendar handlers
+func calendarRequest(w http.ResponseWriter, r *http.Request) (calendar.Language, int, error) {
+	lang, err := requestLanguage(w, r)
+	if err != nil {
+		return "", 0, err
+	}
+	version, err := params.OptionalInt(r, "v", responseV1, responseV2, responseV1)
+	return lang, version, err
+}
+
+// newCalendarDay returns the calendar status of day, with the holiday name in lang. The day is rendered in output, if
+// not nil, while the status is computed in the calendar location. CalDAV errors are logged and the day considered not
+// in holidays.
+func newCalendarDay(cal *calendar.Calendar, day time.Time, lang calendar.Language, output *time.Location) CalendarDay {
+	caldavEvent, err := cal.CaldavEventAt(day)
+	if err != nil {
+		zap.S().Warnf("unable to read holiday status from caldav: %v", err)
+		caldavEvent = nil
+	}
+	holiday, ferie := cal.HolidayAt(day)
+	rendered := day
+	if output != nil {
+		rendered = day.In(output)
+	}
+
+	working, bridge := cal.WorkingDayStatus(day)
+	dayType := calendar.DayOff
+	if working {
+		dayType = calendar.DayFull
+		if cal.IsHalfDay(day) {
+			dayType = calendar.DayHalf
+		}
+	}
+	var workingHoursNow *bool
+	if now := cal.Now().In(cal.Location); sameDay(now, day.In(cal.Location)) {
+		workingHours := cal.IsWorkingHours(now)
+		workingHoursNow = &workingHours
+	}
+
+	return CalendarDay{
+		Day:             rendered,
+		WorkingDay:      working,
+		Ferie:           ferie,
+		Holiday:         caldavEvent != nil,
+		Weekday:         cal.IsWeekDay(day),
+		Bridge:          bridge,
+		Name:            holiday.Localized(lang).Name,
+		Source:          holiday.Source,
+		DayType:         dayType,
+		WorkingHoursNow: workingHoursNow,
+		CaldavEvent:     caldavEvent,
+	}
+}
+
+// requestLanguage returns the language of holiday names requested by the lang parameter or the Accept-Language header
+func requestLanguage(w http.ResponseWriter, r *http.Request) (calendar.Language, error) {
+	w.Header().Add("Vary", "Accept-Language")
+	supported := make([]string, 0, len(calendar.Languages))
+	for _, lang := range calendar.Languages {
+		supported = append(supported, string(lang))
+	}
+	lang, err := params.Language(r, "lang", supported, string(calendar.French))
+	return calendar.Language(lang), err
+}
+
+// CalendarHandler returns the calendar status of the current day, with whether the current time is within working
+// hours
+type CalendarHandler struct {
+	cal    *calendarHolder
+	output *time.Location
+}
+
+func (h *CalendarHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cal := h.cal.Load()
+	lang, version, err := calendarRequest(w, r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	now := cal.Now()
+	// ForRange reads the adjacent days too, for the bridge day status, with a single CalDAV query
+	cal = cal.ForRange(now, now)
+	cd := newCalendarDay(cal, now, lang, h.output)
+	writeJSON(w, calendarDayResponse(version, cd))
+}
+
+// WorkingTodayHandler returns true or false, as plain text, whether the current day of the calendar clock is a
+// working day, for minimal clients polling the service
+type WorkingTodayHandler struct {
+	cal *calendarHolder
+}
+
+func (h *WorkingTodayHandler) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if _, err := w.Write([]byte(strconv.FormatBool(h.cal.Load().IsWorkingDayToday()))); err != nil {
+		zap.S().Errorf("unable to write response: %v", err)
+	}
+}
+
+// CalendarDateHandler returns the calendar status of the date named by the last path segment, such as
+// /calendar/2024-12-25.
+//
+// The status of a date only changes with the configuration, so responses are cached by clients for dateMaxAge and
+// revalidated with an ETag. Today and CalDAV holidays, which change with time or CalDAV edits, are cached for
+// volatileMaxAge only.
+type CalendarDateHandler struct {
+	cal    *calendarHolder
+	output *time.Location
+	prefix string
+}
+
+const (
+	dateMaxAge     = 24 * time.Hour
+	volatileMaxAge = time.Minute
+)
+
+func (h *CalendarDateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cal := h.cal.Load()
+	day, err := params.ParseDate("date", strings.Trim(strings.TrimPrefix(r.URL.Path, h.prefix), "/"), cal.Location)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	lang, version, err := calendarRequest(w, r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	// ForRange reads the adjacent days too, for the bridge day status, with a single CalDAV query
+	cal = cal.ForRange(day, day)
+	cd := newCalendarDay(cal, day, lang, h.output)
+
+	maxAge := dateMaxAge
+	if sameDay(cal.Now().In(cal.Location), day) || cd.Holiday || cd.Source == calendar.SourceCaldav {
+		maxAge = volatileMaxAge
+	}
+	w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%d", int(maxAge.Seconds())))
+	content, err := json.Marshal(calendarDayResponse(version, cd))
+	if err != nil {
+		zap.S().Errorf("unable to marshall response %v: %v", cd, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	// the ETag is computed from the response so that it changes with CalDAV edits as well as the configuration
+	if etagMatches(w, r, contentETag(content)) {
+		return
+	}
+	writeJSONContent(w, content)
+}
+
+// sameDay returns true if a and b are the same calendar day
+func sameDay(a, b time.Time) bool {
+	return a.Year() == b.Year() && a.YearDay() == b.YearDay()
+}
+
+// CalendarWeekHandler returns the calendar status of the 7 days of the week, from Monday or from Sunday if
+// sundayFirst is set, containing the start parameter, the current week by default
+type CalendarWeekHandler struct {
+	cal         *calendarHolder
+	output      *time.Location
+	sundayFirst bool
+}
+
+func (h *CalendarWeekHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cal := h.cal.Load()
+	start, err := params.OptionalDate(r, "start", cal.Location, cal.Now().In(cal.Location))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	lang, version, err := calendarRequest(w, r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// time.Weekday starts on Sunday
+	offset := (int(start.Weekday()) + 6) % 7
+	if h.sundayFirst {
+		offset = int(start.Weekday())
+	}
+	first := time.Date(start.Year(), start.Month(), start.Day()-offset, 0, 0, 0, 0, cal.Location)
+	cal = cal.ForRange(first, first.AddDate(0, 0, 6))
+	days := make([]CalendarDay, 0, 7)
+	for i := 0; i < 7; i++ {
+		days = append(days, newCalendarDay(cal, first.AddDate(0, 0, i), lang, h.output))
+	}
+	writeJSON(w, calendarDaysResponse(version, days))
+}
+
+// maxRangeDays is the maximum number of days returned by CalendarRangeHandler
+const maxRangeDays = 366
+
+// CalendarRangeHandler returns the calendar status of each day between the start and end parameters, inclusive, with
+// a single CalDAV query
+type CalendarRangeHandler struct {
+	cal    *calendarHolder
+	output *time.Location
+}
+
+// dateRange parses the start and end parameters, end being at most maxRangeDays days after start
+func dateRange(r *http.Request, loc *time.Location) (time.Time, time.Time, error) {
+	start, err := params.Date(r, "start", loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	end, err := params.Date(r, "end", loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, &params.Error{Param: "end", Reason: "expected after start", Err: params.ErrOutOfRange}
+	}
+	if end.After(start.AddDate(0, 0, maxRangeDays-1)) {
+		return time.Time{}, time.Time{}, &params.Error{Param: "end", Reason: fmt.Sprintf("expected at most %d days after start", maxRangeDays), Err: params.ErrOutOfRange}
+	}
+	return start, end, nil
+}
+
+func (h *CalendarRangeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cal := h.cal.Load()
+	start, end, err := dateRange(r, cal.Location)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	lang, version, err := calendarRequest(w, r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var days []CalendarDay
+	cal = cal.ForRange(start, end)
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		days = append(days, newCalendarDay(cal, day, lang, h.output))
+	}
+	writeJSON(w, calendarDaysResponse(version, days))
+}
+
+// writeJSON marshals v and writes it as response body. Only a 500 status is written if v can't be marshalled, and
+// write errors are only logged since the status is already sent.
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	content, err := json.Marshal(v)
+	if err != nil {
+		zap.S().Errorf("unable to marshall response %v: %v", v, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	writeJSONContent(w, content)
+}
+
+// writeJSONContent writes content, an already marshalled JSON response
+func writeJSONContent(w http.ResponseWriter, content []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(content); err != nil {
+		zap.S().Errorf("unable to write response: %v", err)
+	}
+}
+
+// RegionRouter dispatches requests to the handler of the region named by the last path segment. Segments starting
+// with a digit are dates, dispatched to the dates handler if any.
+type RegionRouter struct {
+	prefix   string
+	handlers map[string]http.Handler
+	dates    http.Handler
+}
+
+func (rr *RegionRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	region := strings.Trim(strings.TrimPrefix(r.URL.Path, rr.prefix), "/")
+	if rr.dates != nil && region != "" && region[0] >= '0' && region[0] <= '9' {
+		rr.dates.ServeHTTP(w, r)
+		return
+	}
+	h, ok := rr.handlers[region]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown region '%v'", region))
+		return
+	}
+	h.ServeHTTP(w, r)
+}
+
+// DefaultRegionRouter dispatches requests of routes without region, such as /calendar, to the handler of the current
+// region of the -region flag
+type DefaultRegionRouter struct {
+	region   *regionHolder
+	handlers map[calendar.Region]http.Handler
+}
+
+func (rr *DefaultRegionRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	region := rr.region.Load()
+	h, ok := rr.handlers[region]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown region '%v'", region))
+		return
+	}
+	h.ServeHTTP(w, r)
+}
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// writeError writes err as JSON response body with status
+func writeError(w http.ResponseWriter, status int, err error) {
+	content, merr := json.Marshal(ErrorResponse{Error: err.Error()})
+	if merr != nil {
+		zap.S().Errorf("unable to marshall error %v: %v", err, merr)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if _, werr := w.Write(content); werr != nil {
+		zap.S().Errorf("unable to write response: %v", werr)
+	}
+}
+
+type IsHolidayResponse struct {
+	Date      string `json:"date"`
+	IsHoliday bool   `json:"is_holiday"`
+	Name      string `json:"name,omitempty"`
+}
+
+type IsHolidayHandler struct {
+	cal *calendarHolder
+}
+
+func (h *IsHolidayHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cal := h.cal.Load()
+	day, err := params.Date(r, "date", cal.Location)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	lang, err := requestLanguage(w, r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	hol, holiday := cal.HolidayAt(day)
+	resp := IsHolidayResponse{
+		Date:      day.Format(params.DateLayout),
+		IsHoliday: holiday,
+		Name:      hol.Localized(lang).Name,
+	}
+
+	writeJSON(w, resp)
+}
+
+type IsBridgeResponse struct {
+	Date            string `json:"date"`
+	IsBridge        bool   `json:"is_bridge"`
+	AdjacentHoliday string `json:"adjacent_holiday,omitempty"`
+}
+
+// IsBridgeHandler returns whether the date parameter is a bridge day, with the name of the holiday making it a bridge
+type IsBridgeHandler struct {
+	cal *calendarHolder
+}
+
+func (h *IsBridgeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cal := h.cal.Load()
+	day, err := params.Date(r, "date", cal.Location)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	lang, err := requestLanguage(w, r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	hol, bridge := cal.BridgeHoliday(day)
+	resp := IsBridgeResponse{
+		Date:            day.Format(params.DateLayout),
+		IsBridge:        bridge,
+		AdjacentHoliday: hol.Localized(lang).Name,
+	}
+
+	writeJSON(w, resp)
+}
+
+type CacheStatsHandler struct {
+	cal calendarLoader
+}
+
+func (h *CacheStatsHandler) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	cal := h.cal.Load()
+	writeJSON(w, cal.CacheStats())
+}
+
+// maxHolidayYears is the maximum number of years of holidays returned by HolidaysHandler
+const maxHolidayYears = 20
+
+// HolidaysHandler returns the national and CalDAV holidays of the year parameter, the current year by default, with a
+// single CalDAV query. With the from and to parameters, it returns the holidays of each year between them, inclusive.
+type HolidaysHandler struct {
+	cal *calendarHolder
+}
+
+func (h *HolidaysHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cal := h.cal.Load()
+	query := r.URL.Query()
+	if query.Get("from") != "" || query.Get("to") != "" {
+		h.serveYears(w, r, cal)
+		return
+	}
+
+	year, err := params.OptionalYear(r, "year", cal.Now().In(cal.Location).Year())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	lang, err := requestLanguage(w, r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	modTime, cacheable := cal.HolidaysModTime(year), true
+	if cal.HasCaldav() {
+		// CalDAV holidays are modified with their events, their status is unknown on CalDAV error
+		var caldavModTime time.Time
+		cal = cal.ForRange(time.Date(year, time.January, 1, 0, 0, 0, 0, cal.Location), time.Date(year, time.December, 31, 0, 0, 0, 0, cal.Location))
+		caldavModTime, cacheable = cal.CaldavModTime()
+		if caldavModTime.After(modTime) {
+			modTime = caldavModTime
+		}
+	}
+	if cacheable && notModified(w, r, modTime) {
+		return
+	}
+	holidays := cal.HolidaysOfYear(year)
+	localized := make([]calendar.Holiday, 0, len(holidays))
+	for _, hol := range holidays {
+		localized = append(localized, hol.Localized(lang))
+	}
+	writeJSON(w, localized)
+}
+
+type HolidayCountResponse struct {
+	Year  int `json:"year"`
+	Count int `json:"count"`
+}
+
+type HolidayCountHandler struct {
+	cal *calendarHolder
+}
+
+func (h *HolidayCountHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cal := h.cal.Load()
+	year, err := params.OptionalYear(r, "year", cal.Now().In(cal.Location).Year())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, HolidayCountResponse{Year: year, Count: cal.HolidayCount(year)})
+}
+
+type StatsResponse struct {
+	Year        int `json:"year"`
+	TotalDays   int `json:"total_days"`
+	WeekendDays int `json:"weekend_days"`
+	Holidays    int `json:"holidays"`
+	WorkingDays int `json:"working_days"`
+	BridgeDays  int `json:"bridge_days"`
+}
+
+// StatsHandler returns the day counts of the year parameter, current year by default, see calendar.YearStats
+type StatsHandler struct {
+	cal *calendarHolder
+}
+
+func (h *StatsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cal := h.cal.Load()
+	year, err := params.OptionalYear(r, "year", cal.Now().In(cal.Location).Year())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	stats := cal.YearStats(year)
+	writeJSON(w, StatsResponse{
+		Year:        year,
+		TotalDays:   stats.TotalDays,
+		WeekendDays: stats.WeekendDays,
+		Holidays:    stats.Holidays,
+		WorkingDays: stats.WorkingDays,
+		BridgeDays:  stats.BridgeDays,
+	})
+}
+
+// HolidaysOfMonthHandler returns the holidays, national and from CalDAV, of the month of the year and month
+// parameters
+type HolidaysOfMonthHandler struct {
+	cal *calendarHolder
+}
+
+func (h *HolidaysOfMonthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cal := h.cal.Load()
+	year, err := params.Year(r, "year")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	month, err := params.Month(r, "month")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	lang, err := requestLanguage(w, r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	holidays := cal.HolidaysOfMonth(year, month)
+	for i := range holidays {
+		holidays[i] = holidays[i].Localized(lang)
+	}
+	writeJSON(w, holidays)
+}
+
+// defaultUpcomingHolidays is the number of holidays returned by UpcomingHolidaysHandler without n parameter
+const defaultUpcomingHolidays = 3
+
+// UpcomingHolidaysHandler returns the next n holidays, national and from CalDAV, from the current day, crossing years
+type UpcomingHolidaysHandler struct {
+	cal *calendarHolder
+}
+
+func (h *UpcomingHolidaysHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cal := h.cal.Load()
+	n, err := params.OptionalInt(r, "n", 1, calendar.MaxUpcomingHolidays, defaultUpcomingHolidays)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	lang, err := requestLanguage(w, r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	holidays := cal.UpcomingHolidays(cal.Now().In(cal.Location), n)
+	for i := range holidays {
+		holidays[i] = holidays[i].Localized(lang)
+	}
+	writeJSON(w, holidays)
+}
+
+// serveYears writes the holidays of the years between the from and to parameters as a flat array sorted by date
+func (h *HolidaysHandler) serveYears(w http.ResponseWriter, r *http.Request, cal *calendar.Calendar) {
+	from, err := params.Year(r, "from")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	to, err := params.Year(r, "to")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if to < from {
+		writeError(w, http.StatusBadRequest, &params.Error{Param: "to", Reason: "expected at least from", Err: params.ErrOutOfRange})
+		return
+	}
+	if to-from >= maxHolidayYears {
+		writeError(w, http.StatusBadRequest, &params.Error{Param: "to", Reason: fmt.Sprintf("expected a span of at most %d years", maxHolidayYears), Err: params.ErrOutOfRange})
+		return
+	}
+	lang, err := requestLanguage(w, r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	holidays := make([]calendar.Holiday, 0)
+	for year := from; year <= to; year++ {
+		for _, hol := range cal.HolidaysOfYear(year) {
+			holidays = append(holidays, hol.Localized(lang))
+		}
+	}
+	writeJSON(w, holidays)
+}
+
+// notModified sets the Last-Modified header and writes a 304 status if the client copy, as of If-Modified-Since,
+// is up-to-date
+func notModified(w http.ResponseWriter, r *http.Request, modTime time.Time) bool {
+	modTime = modTime.Truncate(time.Second)
+	w.Header().Set("Last-Modified", modTime.UTC().Format(http.TimeFormat))
+
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil || modTime.After(since) {
+		return false
+	}
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// contentETag returns a strong ETag of content, a hash of its bytes
+func contentETag(content []byte) string {
+	h := fnv.New64a()
+	_, _ = h.Write(content)
+	return fmt.Sprintf(`"%x"`, h.Sum64())
+}
+
+// etagMatches sets the ETag header and writes a 304 status if etag is one of the If-None-Match tags of the request
+func etagMatches(w http.ResponseWriter, r *http.Request, etag string) bool {
+	w.Header().Set("ETag", etag)
+
+	for _, tag := range strings.Split(r.Header.Get("If-None-Match"), ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == etag || tag == "*" {
+			w.WriteHeader(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+type WorkingDaysInMonthHandler struct {
+	cal *calendarHolder
+}
+
+func (h *WorkingDaysInMonthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cal := h.cal.Load()
+	year, err := params.Year(r, "year")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	month, err := params.Month(r, "month")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	days := cal.WorkingDaysInMonth(year, month)
+	if days == nil {
+		days = []time.Time{}
+	}
+	writeJSON(w, days)
+}
+
+// WorkingDaysHandler returns each working day between the start and end parameters, inclusive
+type WorkingDaysHandler struct {
+	cal *calendarHolder
+}
+
+func (h *WorkingDaysHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cal := h.cal.Load()
+	start, end, err := dateRange(r, cal.Location)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	days := cal.WorkingDays(start, end)
+	if days == nil {
+		days = []time.Time{}
+	}
+	writeJSON(w, days)
+}
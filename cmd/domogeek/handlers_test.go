@@ -0,0 +1,1460 @@
+package main
+
+import (
+	"domogeek/pkg/calendar"
+	"encoding/json"
+	"fmt"
+	"github.com/dolanor/caldav-go/caldav/entities"
+	"github.com/dolanor/caldav-go/icalendar/components"
+	"github.com/dolanor/caldav-go/icalendar/values"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type MockCaldav struct {
+	events []*components.Event
+}
+
+func (m *MockCaldav) QueryEvents(_ string, _ *entities.CalendarQuery) ([]*components.Event, error) {
+	return m.events, nil
+}
+
+func newTestCalendar(t *testing.T, opts ...calendar.Option) *calendar.Calendar {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatalf("unable to load time location: %v", err)
+	}
+	return calendar.New(loc, opts...)
+}
+
+func fixedClock(t time.Time) func() time.Time {
+	return func() time.Time {
+		return t
+	}
+}
+
+// CountingCaldav counts the queries of the wrapped MockCaldav
+type CountingCaldav struct {
+	MockCaldav
+	queries int
+}
+
+func (m *CountingCaldav) QueryEvents(path string, query *entities.CalendarQuery) ([]*components.Event, error) {
+	m.queries++
+	return m.MockCaldav.QueryEvents(path, query)
+}
+
+func TestCalendarHandler_ServeHTTP(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatalf("unable to load time location: %v", err)
+	}
+	now := time.Date(2022, time.April, 13, 10, 0, 0, 0, loc)
+	cal := newTestCalendar(t,
+		calendar.WithCaldav(&MockCaldav{
+			events: []*components.Event{
+				{
+					UID:       "1",
+					DateStart: values.NewDateTime(time.Date(2022, time.April, 13, 0, 0, 0, 0, time.UTC)),
+					DateEnd:   values.NewDateTime(time.Date(2022, time.April, 14, 0, 0, 0, 0, time.UTC)),
+					Summary:   "Holidays",
+				},
+			},
+		}),
+		calendar.WithCaldavSummaryPattern("Holidays"),
+		calendar.WithClock(fixedClock(now)),
+	)
+
+	h := &CalendarHandler{cal: newCalendarHolder(cal)}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/calendar", nil))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("bad status code: %v", w.Code)
+	}
+	var cd CalendarDay
+	if err := json.Unmarshal(w.Body.Bytes(), &cd); err != nil {
+		t.Fatalf("unable to unmarshal response: %v", err)
+	}
+	if !cd.Day.Equal(now) {
+		t.Errorf("bad day, expected:%v ; actual:%v", now, cd.Day)
+	}
+	if !cd.Holiday || !cd.Ferie || cd.WorkingDay || !cd.Weekday {
+		t.Errorf("bad calendar day for caldav holiday: %+v", cd)
+	}
+	if cd.CaldavEvent == nil || cd.CaldavEvent.UID != "1" || cd.CaldavEvent.Summary != "Holidays" {
+		t.Errorf("bad caldav event, expected:{1 Holidays} ; actual:%v", cd.CaldavEvent)
+	}
+}
+
+func TestCalendarHandler_WorkingHoursNow(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatalf("unable to load time location: %v", err)
+	}
+	tests := []struct {
+		name string
+		now  time.Time
+		want bool
+	}{
+		{name: "Working hours", now: time.Date(2024, time.May, 6, 9, 0, 0, 0, loc), want: true},
+		{name: "Evening", now: time.Date(2024, time.May, 6, 18, 0, 0, 0, loc), want: false},
+		{name: "Weekend", now: time.Date(2024, time.May, 4, 10, 0, 0, 0, loc), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cal := newTestCalendar(t,
+				calendar.WithWorkingHours(calendar.WorkingHours{Start: 9 * time.Hour, End: 18 * time.Hour}),
+				calendar.WithClock(fixedClock(tt.now)),
+			)
+			h := &CalendarHandler{cal: newCalendarHolder(cal)}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/calendar", nil))
+
+			var cd CalendarDay
+			if err := json.Unmarshal(w.Body.Bytes(), &cd); err != nil {
+				t.Fatalf("unable to unmarshal response: %v", err)
+			}
+			if cd.WorkingHoursNow == nil {
+				t.Fatalf("missing working hours status: %v", w.Body.String())
+			}
+			if *cd.WorkingHoursNow != tt.want {
+				t.Errorf("bad working hours status, expected:%v ; actual:%v", tt.want, *cd.WorkingHoursNow)
+			}
+		})
+	}
+}
+
+func TestCalendarDateHandler_WorkingHoursNow(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatalf("unable to load time location: %v", err)
+	}
+	now := time.Date(2024, time.May, 6, 10, 0, 0, 0, loc)
+	cal := newTestCalendar(t,
+		calendar.WithWorkingHours(calendar.WorkingHours{Start: 9 * time.Hour, End: 18 * time.Hour}),
+		calendar.WithClock(fixedClock(now)),
+	)
+	h := &CalendarDateHandler{cal: newCalendarHolder(cal), prefix: "/calendar/"}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/calendar/2024-05-06", `"working_hours_now":true`},
+		{"/calendar/2024-05-06?v=2", `"is_working_hours":true`},
+		{"/calendar/2024-05-07", ""},
+		{"/calendar/2024-05-07?v=2", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			body := w.Body.String()
+			if tt.want != "" && !strings.Contains(body, tt.want) {
+				t.Errorf("missing working hours status %v: %v", tt.want, body)
+			}
+			if tt.want == "" && strings.Contains(body, "working_hours") {
+				t.Errorf("working hours status should only be set for the current day: %v", body)
+			}
+		})
+	}
+}
+
+func TestWorkingTodayHandler_ServeHTTP(t *testing.T) {
+	cal := newTestCalendar(t)
+	tests := []struct {
+		name string
+		now  time.Time
+		want string
+	}{
+		{name: "Weekday", now: time.Date(2024, time.May, 6, 10, 0, 0, 0, cal.Location), want: "true"},
+		{name: "Weekend", now: time.Date(2024, time.May, 4, 10, 0, 0, 0, cal.Location), want: "false"},
+		{name: "Holiday", now: time.Date(2024, time.May, 8, 10, 0, 0, 0, cal.Location), want: "false"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &WorkingTodayHandler{cal: newCalendarHolder(newTestCalendar(t, calendar.WithClock(fixedClock(tt.now))))}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/working-today", nil))
+			if w.Code != http.StatusOK {
+				t.Errorf("bad status code, expected:%v ; actual:%v", http.StatusOK, w.Code)
+			}
+			if w.Body.String() != tt.want {
+				t.Errorf("bad body, expected:%v ; actual:%v", tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestCalendarHandler_Source(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatalf("unable to load time location: %v", err)
+	}
+	caldav := calendar.WithCaldav(&MockCaldav{
+		events: []*components.Event{
+			{
+				UID:       "1",
+				DateStart: values.NewDateTime(time.Date(2022, time.April, 13, 0, 0, 0, 0, time.UTC)),
+				DateEnd:   values.NewDateTime(time.Date(2022, time.April, 14, 0, 0, 0, 0, time.UTC)),
+				Summary:   "Holidays",
+			},
+		},
+	})
+	tests := []struct {
+		name string
+		day  time.Time
+		want string
+	}{
+		{name: "national", day: time.Date(2022, time.July, 14, 10, 0, 0, 0, loc), want: "national"},
+		{name: "caldav", day: time.Date(2022, time.April, 13, 10, 0, 0, 0, loc), want: "caldav"},
+		{name: "none", day: time.Date(2022, time.April, 12, 10, 0, 0, 0, loc), want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cal := newTestCalendar(t, caldav, calendar.WithCaldavSummaryPattern("Holidays"), calendar.WithClock(fixedClock(tt.day)))
+			h := &CalendarHandler{cal: newCalendarHolder(cal)}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/calendar", nil))
+
+			var cd CalendarDay
+			if err := json.Unmarshal(w.Body.Bytes(), &cd); err != nil {
+				t.Fatalf("unable to unmarshal response: %v", err)
+			}
+			if cd.Source != tt.want {
+				t.Errorf("bad source, expected:%v ; actual:%v", tt.want, cd.Source)
+			}
+			if cd.Ferie != (tt.want != "") {
+				t.Errorf("bad ferie for source %q: %+v", tt.want, cd)
+			}
+		})
+	}
+}
+
+func TestRegionRouter_ServeHTTP(t *testing.T) {
+	called := ""
+	handler := func(region string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = region
+		})
+	}
+	rr := &RegionRouter{
+		prefix: "/calendar/",
+		handlers: map[string]http.Handler{
+			"metropole":      handler("metropole"),
+			"alsace-moselle": handler("alsace-moselle"),
+		},
+		dates: handler("date"),
+	}
+
+	tests := []struct {
+		path       string
+		wantCode   int
+		wantRegion string
+	}{
+		{"/calendar/metropole", http.StatusOK, "metropole"},
+		{"/calendar/alsace-moselle", http.StatusOK, "alsace-moselle"},
+		{"/calendar/alsace-moselle/", http.StatusOK, "alsace-moselle"},
+		{"/calendar/unknown", http.StatusNotFound, ""},
+		{"/calendar/2024-12-25", http.StatusOK, "date"},
+		{"/calendar/2024-13-45", http.StatusOK, "date"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			called = ""
+			w := httptest.NewRecorder()
+			rr.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != tt.wantCode {
+				t.Errorf("bad status code, expected:%v ; actual:%v", tt.wantCode, w.Code)
+			}
+			if called != tt.wantRegion {
+				t.Errorf("bad region handler, expected:%v ; actual:%v", tt.wantRegion, called)
+			}
+		})
+	}
+}
+
+func TestDefaultRegionRouter_ServeHTTP(t *testing.T) {
+	called := ""
+	handler := func(region string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = region
+		})
+	}
+	regions := &regionHolder{region: calendar.RegionMetropole}
+	rr := &DefaultRegionRouter{
+		region: regions,
+		handlers: map[calendar.Region]http.Handler{
+			calendar.RegionMetropole: handler("metropole"),
+			calendar.RegionCorse:     handler("corse"),
+		},
+	}
+
+	tests := []struct {
+		region     calendar.Region
+		wantCode   int
+		wantRegion string
+	}{
+		{calendar.RegionMetropole, http.StatusOK, "metropole"},
+		{calendar.RegionCorse, http.StatusOK, "corse"},
+		{calendar.RegionAlsaceMoselle, http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.region), func(t *testing.T) {
+			called = ""
+			regions.Store(tt.region)
+			w := httptest.NewRecorder()
+			rr.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/calendar", nil))
+			if w.Code != tt.wantCode {
+				t.Errorf("bad status code, expected:%v ; actual:%v", tt.wantCode, w.Code)
+			}
+			if called != tt.wantRegion {
+				t.Errorf("bad region handler, expected:%v ; actual:%v", tt.wantRegion, called)
+			}
+		})
+	}
+}
+
+func TestCalendarDayHandlers_SingleQuery(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatalf("unable to load time location: %v", err)
+	}
+	// Friday after Ascension, a bridge day
+	now := time.Date(2024, time.May, 10, 10, 0, 0, 0, loc)
+	cdav := &CountingCaldav{}
+	cal := newTestCalendar(t,
+		calendar.WithCaldav(cdav),
+		calendar.WithCaldavSummaryPattern("Holidays"),
+		calendar.WithClock(fixedClock(now)),
+	)
+
+	tests := []struct {
+		name    string
+		handler http.Handler
+		url     string
+	}{
+		{name: "Today", handler: &CalendarHandler{cal: newCalendarHolder(cal)}, url: "/calendar"},
+		{name: "Date", handler: &CalendarDateHandler{cal: newCalendarHolder(cal), prefix: "/calendar/"}, url: "/calendar/2024-05-10"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cdav.queries = 0
+			w := httptest.NewRecorder()
+			tt.handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+
+			var cd CalendarDay
+			if err := json.Unmarshal(w.Body.Bytes(), &cd); err != nil {
+				t.Fatalf("unable to unmarshal response: %v", err)
+			}
+			if !cd.Bridge {
+				t.Errorf("%v should be a bridge day: %v", now, w.Body.String())
+			}
+			if cdav.queries != 1 {
+				t.Errorf("bad caldav queries count, expected:1 ; actual:%v", cdav.queries)
+			}
+		})
+	}
+}
+
+func TestCalendarDateHandler_ServeHTTP(t *testing.T) {
+	cal := newTestCalendar(t)
+	h := &CalendarDateHandler{cal: newCalendarHolder(cal), prefix: "/calendar/"}
+
+	tests := []struct {
+		path     string
+		wantCode int
+		wantDay  time.Time
+	}{
+		{"/calendar/2024-12-25", http.StatusOK, time.Date(2024, time.December, 25, 0, 0, 0, 0, cal.Location)},
+		{"/calendar/2024-12-25/", http.StatusOK, time.Date(2024, time.December, 25, 0, 0, 0, 0, cal.Location)},
+		{"/calendar/2024-13-45", http.StatusBadRequest, time.Time{}},
+		{"/calendar/25-12-2024", http.StatusBadRequest, time.Time{}},
+		{"/calendar/1582-12-25", http.StatusBadRequest, time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != tt.wantCode {
+				t.Fatalf("bad status code, expected:%v ; actual:%v", tt.wantCode, w.Code)
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			var cd CalendarDay
+			if err := json.Unmarshal(w.Body.Bytes(), &cd); err != nil {
+				t.Fatalf("unable to unmarshal response: %v", err)
+			}
+			if !cd.Day.Equal(tt.wantDay) {
+				t.Errorf("bad day, expected:%v ; actual:%v", tt.wantDay, cd.Day)
+			}
+			if !cd.Ferie || cd.Name != "Noël" {
+				t.Errorf("bad calendar day for Christmas: %+v", cd)
+			}
+		})
+	}
+}
+
+func TestCalendarDateHandler_OutputLocation(t *testing.T) {
+	cal := newTestCalendar(t)
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("unable to load time location: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		output *time.Location
+		want   string
+	}{
+		{name: "Calendar location", want: `"2024-12-25T00:00:00+01:00"`},
+		{name: "UTC", output: time.UTC, want: `"2024-12-24T23:00:00Z"`},
+		{name: "New York", output: newYork, want: `"2024-12-24T18:00:00-05:00"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &CalendarDateHandler{cal: newCalendarHolder(cal), output: tt.output, prefix: "/calendar/"}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/calendar/2024-12-25", nil))
+
+			var raw map[string]json.RawMessage
+			if err := json.Unmarshal(w.Body.Bytes(), &raw); err != nil {
+				t.Fatalf("unable to unmarshal response: %v", err)
+			}
+			if string(raw["day"]) != tt.want {
+				t.Errorf("bad day, expected:%v ; actual:%v", tt.want, string(raw["day"]))
+			}
+			if string(raw["ferie"]) != "true" {
+				t.Errorf("holiday should be computed in the calendar location: %v", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestCalendarDateHandler_Cache(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatalf("unable to load time location: %v", err)
+	}
+	cal := newTestCalendar(t,
+		calendar.WithCaldav(&MockCaldav{
+			events: []*components.Event{
+				{
+					UID:       "1",
+					DateStart: values.NewDateTime(time.Date(2022, time.April, 13, 0, 0, 0, 0, time.UTC)),
+					DateEnd:   values.NewDateTime(time.Date(2022, time.April, 14, 0, 0, 0, 0, time.UTC)),
+					Summary:   "Holidays",
+				},
+			},
+		}),
+		calendar.WithCaldavSummaryPattern("Holidays"),
+		calendar.WithClock(fixedClock(time.Date(2022, time.April, 20, 15, 0, 0, 0, loc))),
+	)
+	h := &CalendarDateHandler{cal: newCalendarHolder(cal), prefix: "/calendar/"}
+
+	tests := []struct {
+		path       string
+		wantMaxAge string
+	}{
+		{"/calendar/2022-07-14", "max-age=86400"},
+		{"/calendar/2022-04-12", "max-age=86400"},
+		{"/calendar/2022-04-20", "max-age=60"},
+		{"/calendar/2022-04-13", "max-age=60"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("bad status code, expected:%v ; actual:%v", http.StatusOK, w.Code)
+			}
+			if cc := w.Header().Get("Cache-Control"); cc != tt.wantMaxAge {
+				t.Errorf("bad Cache-Control, expected:%v ; actual:%v", tt.wantMaxAge, cc)
+			}
+			etag := w.Header().Get("ETag")
+			if etag == "" {
+				t.Fatal("missing ETag")
+			}
+
+			r := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			r.Header.Set("If-None-Match", `"other", `+etag)
+			w = httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			if w.Code != http.StatusNotModified {
+				t.Errorf("bad status code for matching ETag, expected:%v ; actual:%v", http.StatusNotModified, w.Code)
+			}
+			if w.Body.Len() != 0 {
+				t.Errorf("body should be empty on 304: %v", w.Body.String())
+			}
+		})
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/calendar/2022-07-14", nil))
+	other := httptest.NewRecorder()
+	h.ServeHTTP(other, httptest.NewRequest(http.MethodGet, "/calendar/2022-07-15", nil))
+	if w.Header().Get("ETag") == other.Header().Get("ETag") {
+		t.Errorf("ETag should change with the date: %v", w.Header().Get("ETag"))
+	}
+	h.cal.Store(calendar.New(cal.Location, calendar.WithExcludedHolidays("07-14")))
+	reconfigured := httptest.NewRecorder()
+	h.ServeHTTP(reconfigured, httptest.NewRequest(http.MethodGet, "/calendar/2022-07-14", nil))
+	if w.Header().Get("ETag") == reconfigured.Header().Get("ETag") {
+		t.Errorf("ETag should change with the configuration: %v", w.Header().Get("ETag"))
+	}
+}
+
+func TestCalendarDateHandler_CacheCaldavEdit(t *testing.T) {
+	cdav := &MockCaldav{}
+	cal := newTestCalendar(t, calendar.WithCaldav(cdav), calendar.WithCaldavSummaryPattern("Holidays"))
+	h := &CalendarDateHandler{cal: newCalendarHolder(cal), prefix: "/calendar/"}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/calendar/2022-04-12", nil))
+	etag := w.Header().Get("ETag")
+
+	cdav.events = []*components.Event{
+		{
+			UID:       "1",
+			DateStart: values.NewDateTime(time.Date(2022, time.April, 12, 0, 0, 0, 0, time.UTC)),
+			DateEnd:   values.NewDateTime(time.Date(2022, time.April, 13, 0, 0, 0, 0, time.UTC)),
+			Summary:   "Holidays",
+		},
+	}
+	r := httptest.NewRequest(http.MethodGet, "/calendar/2022-04-12", nil)
+	r.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("bad status code after a caldav edit, expected:%v ; actual:%v", http.StatusOK, w.Code)
+	}
+	if w.Header().Get("ETag") == etag {
+		t.Errorf("ETag should change with caldav events: %v", etag)
+	}
+	if !strings.Contains(w.Body.String(), `"ferie":true`) {
+		t.Errorf("caldav holiday should be returned: %v", w.Body.String())
+	}
+}
+
+func TestCalendarDateHandler_DayType(t *testing.T) {
+	cal := newTestCalendar(t, calendar.WithHalfDays("12-24"))
+	h := &CalendarDateHandler{cal: newCalendarHolder(cal), prefix: "/calendar/"}
+	tests := []struct {
+		date string
+		want string
+	}{
+		{date: "2024-12-23", want: calendar.DayFull},
+		{date: "2024-12-24", want: calendar.DayHalf},
+		{date: "2024-12-25", want: calendar.DayOff},
+	}
+	for _, tt := range tests {
+		t.Run(tt.date, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/calendar/"+tt.date, nil))
+			var cd CalendarDay
+			if err := json.Unmarshal(w.Body.Bytes(), &cd); err != nil {
+				t.Fatalf("unable to unmarshal response: %v", err)
+			}
+			if cd.DayType != tt.want {
+				t.Errorf("bad day type, expected:%v ; actual:%v", tt.want, cd.DayType)
+			}
+			if cd.WorkingDay != (tt.want != calendar.DayOff) {
+				t.Errorf("bad working day status: %+v", cd)
+			}
+		})
+	}
+}
+
+func TestCalendarDateHandler_ResponseVersion(t *testing.T) {
+	cal := newTestCalendar(t)
+	h := &CalendarDateHandler{cal: newCalendarHolder(cal), prefix: "/calendar/"}
+	tests := []struct {
+		name    string
+		url     string
+		want    map[string]interface{}
+		missing []string
+	}{
+		{
+			name:    "Default version",
+			url:     "/calendar/2022-07-14",
+			want:    map[string]interface{}{"working_day": false, "ferie": true, "holiday": false, "weekday": true, "name": "Fête nationale"},
+			missing: []string{"is_working_day", "is_holiday", "holiday_name"},
+		},
+		{
+			name:    "Version 1",
+			url:     "/calendar/2022-07-14?v=1",
+			want:    map[string]interface{}{"working_day": false, "ferie": true, "name": "Fête nationale"},
+			missing: []string{"is_working_day", "is_holiday", "holiday_name"},
+		},
+		{
+			name:    "Version 2",
+			url:     "/calendar/2022-07-14?v=2",
+			want:    map[string]interface{}{"is_working_day": false, "is_holiday": true, "is_vacation": false, "is_weekday": true, "holiday_name": "Fête nationale"},
+			missing: []string{"working_day", "ferie", "holiday", "name"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("bad status code, expected:%v ; actual:%v", http.StatusOK, w.Code)
+			}
+			var fields map[string]interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &fields); err != nil {
+				t.Fatalf("unable to unmarshal response: %v", err)
+			}
+			for name, want := range tt.want {
+				if fields[name] != want {
+					t.Errorf("bad %v, expected:%v ; actual:%v", name, want, fields[name])
+				}
+			}
+			for _, name := range tt.missing {
+				if _, ok := fields[name]; ok {
+					t.Errorf("unexpected field %v in %v", name, w.Body.String())
+				}
+			}
+		})
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/calendar/2022-07-14?v=3", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("bad status code for unknown version, expected:%v ; actual:%v", http.StatusBadRequest, w.Code)
+	}
+
+	v1, v2 := httptest.NewRecorder(), httptest.NewRecorder()
+	h.ServeHTTP(v1, httptest.NewRequest(http.MethodGet, "/calendar/2022-07-14", nil))
+	h.ServeHTTP(v2, httptest.NewRequest(http.MethodGet, "/calendar/2022-07-14?v=2", nil))
+	if v1.Header().Get("ETag") == v2.Header().Get("ETag") {
+		t.Errorf("ETag should change with the version: %v", v1.Header().Get("ETag"))
+	}
+}
+
+func TestCalendarRangeHandler_ResponseVersion(t *testing.T) {
+	cal := newTestCalendar(t)
+	h := &CalendarRangeHandler{cal: newCalendarHolder(cal)}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/calendar/range?start=2024-12-24&end=2024-12-25&v=2", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("bad status code, expected:%v ; actual:%v", http.StatusOK, w.Code)
+	}
+	var days []CalendarDayV2
+	if err := json.Unmarshal(w.Body.Bytes(), &days); err != nil {
+		t.Fatalf("unable to unmarshal response: %v", err)
+	}
+	if len(days) != 2 {
+		t.Fatalf("bad days count, expected:2 ; actual:%v", len(days))
+	}
+	if !days[0].IsWorkingDay || days[0].IsHoliday {
+		t.Errorf("bad status of 2024-12-24: %+v", days[0])
+	}
+	if days[1].IsWorkingDay || !days[1].IsHoliday || days[1].HolidayName != "Noël" {
+		t.Errorf("bad status of 2024-12-25: %+v", days[1])
+	}
+}
+
+// failingResponseWriter records written statuses and fails on each body write
+type failingResponseWriter struct {
+	header   http.Header
+	statuses []int
+	writes   int
+}
+
+func (f *failingResponseWriter) Header() http.Header {
+	if f.header == nil {
+		f.header = make(http.Header)
+	}
+	return f.header
+}
+
+func (f *failingResponseWriter) Write(_ []byte) (int, error) {
+	f.writes++
+	return 0, fmt.Errorf("connection closed")
+}
+
+func (f *failingResponseWriter) WriteHeader(statusCode int) {
+	f.statuses = append(f.statuses, statusCode)
+}
+
+func TestWriteJSON(t *testing.T) {
+	t.Run("Marshal failure", func(t *testing.T) {
+		w := &failingResponseWriter{}
+		writeJSON(w, math.Inf(1))
+		if len(w.statuses) != 1 || w.statuses[0] != http.StatusInternalServerError {
+			t.Errorf("a single 500 status should be written, got %v", w.statuses)
+		}
+		if w.writes != 0 {
+			t.Errorf("no body should be written, got %d writes", w.writes)
+		}
+	})
+	t.Run("Write failure", func(t *testing.T) {
+		w := &failingResponseWriter{}
+		writeJSON(w, CalendarDay{})
+		if len(w.statuses) != 0 {
+			t.Errorf("no status should be written after a write failure, got %v", w.statuses)
+		}
+		if w.writes != 1 {
+			t.Errorf("body should be written once, got %d writes", w.writes)
+		}
+	})
+}
+
+func TestIsHolidayHandler_ServeHTTP(t *testing.T) {
+	h := &IsHolidayHandler{cal: newCalendarHolder(newTestCalendar(t))}
+
+	tests := []struct {
+		name     string
+		url      string
+		wantCode int
+		want     IsHolidayResponse
+		wantErr  string
+	}{
+		{
+			name:     "Holiday",
+			url:      "/is-holiday?date=2024-05-08",
+			wantCode: http.StatusOK,
+			want:     IsHolidayResponse{Date: "2024-05-08", IsHoliday: true, Name: "Victoire 1945"},
+		},
+		{
+			name:     "Ordinary day",
+			url:      "/is-holiday?date=2024-05-07",
+			wantCode: http.StatusOK,
+			want:     IsHolidayResponse{Date: "2024-05-07"},
+		},
+		{
+			name:     "Missing date",
+			url:      "/is-holiday",
+			wantCode: http.StatusBadRequest,
+			wantErr:  "missing parameter 'date'",
+		},
+		{
+			name:     "Bad date",
+			url:      "/is-holiday?date=2024-13-01",
+			wantCode: http.StatusBadRequest,
+			wantErr:  "invalid parameter 'date': expected format is YYYY-MM-DD",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+			if w.Code != tt.wantCode {
+				t.Errorf("bad status code, expected:%v ; actual:%v", tt.wantCode, w.Code)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+				t.Errorf("bad content type: %v", ct)
+			}
+			if tt.wantErr != "" {
+				var resp ErrorResponse
+				if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("unable to unmarshal response: %v", err)
+				}
+				if resp.Error != tt.wantErr {
+					t.Errorf("bad error, expected:%v ; actual:%v", tt.wantErr, resp.Error)
+				}
+				return
+			}
+			var resp IsHolidayResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("unable to unmarshal response: %v", err)
+			}
+			if resp != tt.want {
+				t.Errorf("bad response, expected:%+v ; actual:%+v", tt.want, resp)
+			}
+		})
+	}
+}
+
+func TestIsBridgeHandler_ServeHTTP(t *testing.T) {
+	h := &IsBridgeHandler{cal: newCalendarHolder(newTestCalendar(t))}
+
+	tests := []struct {
+		name     string
+		url      string
+		wantCode int
+		want     IsBridgeResponse
+	}{
+		{
+			name:     "Friday after Ascension",
+			url:      "/is-bridge?date=2024-05-10",
+			wantCode: http.StatusOK,
+			want:     IsBridgeResponse{Date: "2024-05-10", IsBridge: true, AdjacentHoliday: "Ascension"},
+		},
+		{
+			name:     "Monday before armistice",
+			url:      "/is-bridge?date=2025-11-10",
+			wantCode: http.StatusOK,
+			want:     IsBridgeResponse{Date: "2025-11-10", IsBridge: true, AdjacentHoliday: "Armistice 1918"},
+		},
+		{
+			name:     "Ordinary day",
+			url:      "/is-bridge?date=2024-05-14",
+			wantCode: http.StatusOK,
+			want:     IsBridgeResponse{Date: "2024-05-14"},
+		},
+		{
+			name:     "Bad date",
+			url:      "/is-bridge?date=2024-02-30",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "Missing date",
+			url:      "/is-bridge",
+			wantCode: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+			if w.Code != tt.wantCode {
+				t.Fatalf("bad status code, expected:%v ; actual:%v", tt.wantCode, w.Code)
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			var resp IsBridgeResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("unable to unmarshal response: %v", err)
+			}
+			if resp != tt.want {
+				t.Errorf("bad response, expected:%+v ; actual:%+v", tt.want, resp)
+			}
+		})
+	}
+}
+
+func TestHolidaysHandler_LastModified(t *testing.T) {
+	h := &HolidaysHandler{cal: newCalendarHolder(newTestCalendar(t))}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/holidays?year=2024", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("bad status code: %v", w.Code)
+	}
+	lastModified := w.Header().Get("Last-Modified")
+	if lastModified == "" {
+		t.Fatal("Last-Modified header should be set")
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/holidays?year=2024", nil)
+	r.Header.Set("If-Modified-Since", lastModified)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusNotModified {
+		t.Errorf("bad status code, expected:%v ; actual:%v", http.StatusNotModified, w.Code)
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("304 response should not have a body: %v", w.Body.String())
+	}
+
+	r = httptest.NewRequest(http.MethodGet, "/holidays?year=2024", nil)
+	r.Header.Set("If-Modified-Since", time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC).Format(http.TimeFormat))
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Errorf("bad status code for outdated client copy: %v", w.Code)
+	}
+}
+
+func TestHolidaysHandler_LastModifiedCaldav(t *testing.T) {
+	modified := time.Now().Add(24 * time.Hour).Truncate(time.Second).UTC()
+	event := &components.Event{
+		UID:          "1",
+		DateStamp:    values.NewDateTime(modified.Add(-time.Hour)),
+		DateStart:    values.NewDateTime(time.Date(2024, time.May, 31, 0, 0, 0, 0, time.UTC)),
+		DateEnd:      values.NewDateTime(time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)),
+		LastModified: values.NewDateTime(modified),
+		Summary:      "Holidays",
+	}
+	cal := newTestCalendar(t, calendar.WithCaldav(&MockCaldav{events: []*components.Event{event}}), calendar.WithCaldavSummaryPattern("Holidays"))
+	h := &HolidaysHandler{cal: newCalendarHolder(cal)}
+	get := func(ifModifiedSince string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/holidays?year=2024", nil)
+		if ifModifiedSince != "" {
+			r.Header.Set("If-Modified-Since", ifModifiedSince)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	w := get("")
+	lastModified := w.Header().Get("Last-Modified")
+	if want := modified.Format(http.TimeFormat); lastModified != want {
+		t.Fatalf("bad Last-Modified header, expected:%v ; actual:%v", want, lastModified)
+	}
+	if w = get(lastModified); w.Code != http.StatusNotModified {
+		t.Errorf("bad status code, expected:%v ; actual:%v", http.StatusNotModified, w.Code)
+	}
+
+	// the event is edited
+	event.LastModified = values.NewDateTime(modified.Add(time.Hour))
+	if w = get(lastModified); w.Code != http.StatusOK {
+		t.Errorf("bad status code after caldav event edit, expected:%v ; actual:%v", http.StatusOK, w.Code)
+	}
+
+	// without LAST-MODIFIED, DTSTAMP is used
+	event.LastModified = nil
+	if w = get(""); w.Header().Get("Last-Modified") != modified.Add(-time.Hour).Format(http.TimeFormat) {
+		t.Errorf("bad Last-Modified header from DTSTAMP: %v", w.Header().Get("Last-Modified"))
+	}
+}
+
+func TestHolidaysHandler_LastModifiedCaldavError(t *testing.T) {
+	cal := newTestCalendar(t, calendar.WithCaldav(&FailingCaldav{failures: 10}), calendar.WithCaldavSummaryPattern("Holidays"))
+	h := &HolidaysHandler{cal: newCalendarHolder(cal)}
+
+	r := httptest.NewRequest(http.MethodGet, "/holidays?year=2024", nil)
+	r.Header.Set("If-Modified-Since", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Errorf("bad status code on caldav error, expected:%v ; actual:%v", http.StatusOK, w.Code)
+	}
+	if lastModified := w.Header().Get("Last-Modified"); lastModified != "" {
+		t.Errorf("Last-Modified header should not be set on caldav error: %v", lastModified)
+	}
+}
+
+func TestHolidaysHandler_Years(t *testing.T) {
+	cal := newTestCalendar(t,
+		calendar.WithCaldav(&MockCaldav{
+			events: []*components.Event{
+				{
+					UID:       "1",
+					DateStart: values.NewDateTime(time.Date(2025, time.April, 14, 0, 0, 0, 0, time.UTC)),
+					DateEnd:   values.NewDateTime(time.Date(2025, time.April, 15, 0, 0, 0, 0, time.UTC)),
+					Summary:   "Holidays",
+				},
+			},
+		}),
+		calendar.WithCaldavSummaryPattern("Holidays"),
+	)
+	h := &HolidaysHandler{cal: newCalendarHolder(cal)}
+
+	tests := []struct {
+		name      string
+		url       string
+		wantCode  int
+		wantCount int
+	}{
+		{name: "Several years", url: "/holidays?from=2024&to=2026", wantCode: http.StatusOK, wantCount: 3*11 + 1},
+		{name: "Single year", url: "/holidays?from=2024&to=2024", wantCode: http.StatusOK, wantCount: 11},
+		{name: "Year with CalDAV holidays", url: "/holidays?year=2025", wantCode: http.StatusOK, wantCount: 12},
+		{name: "To before from", url: "/holidays?from=2025&to=2024", wantCode: http.StatusBadRequest},
+		{name: "Too many years", url: "/holidays?from=2000&to=2020", wantCode: http.StatusBadRequest},
+		{name: "Missing to", url: "/holidays?from=2024", wantCode: http.StatusBadRequest},
+		{name: "Invalid from", url: "/holidays?from=next&to=2024", wantCode: http.StatusBadRequest},
+		{name: "Year before Gregorian calendar", url: "/holidays?year=1582", wantCode: http.StatusBadRequest},
+		{name: "First year", url: "/holidays?year=1583", wantCode: http.StatusOK, wantCount: 11},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+			if w.Code != tt.wantCode {
+				t.Fatalf("bad status code, expected:%v ; actual:%v", tt.wantCode, w.Code)
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			var holidays []calendar.Holiday
+			if err := json.Unmarshal(w.Body.Bytes(), &holidays); err != nil {
+				t.Fatalf("unable to unmarshal response: %v", err)
+			}
+			if len(holidays) != tt.wantCount {
+				t.Errorf("bad number of holidays, expected:%v ; actual:%v", tt.wantCount, len(holidays))
+			}
+			for i := 1; i < len(holidays); i++ {
+				if !holidays[i-1].Date.Before(holidays[i].Date) {
+					t.Errorf("holidays should be sorted by date: %v", holidays)
+				}
+			}
+		})
+	}
+}
+
+func TestCalendarWeekHandler_ServeHTTP(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatalf("unable to load time location: %v", err)
+	}
+	cal := newTestCalendar(t, calendar.WithCaldav(&MockCaldav{}), calendar.WithClock(fixedClock(time.Date(2024, time.May, 9, 10, 0, 0, 0, loc))))
+	monday := time.Date(2024, time.May, 6, 0, 0, 0, 0, cal.Location)
+	h := &CalendarWeekHandler{cal: newCalendarHolder(cal)}
+
+	tests := []struct {
+		name       string
+		url        string
+		wantStatus int
+	}{
+		{name: "Monday", url: "/calendar/week?start=2024-05-06", wantStatus: http.StatusOK},
+		{name: "Sunday", url: "/calendar/week?start=2024-05-12", wantStatus: http.StatusOK},
+		{name: "Current week", url: "/calendar/week", wantStatus: http.StatusOK},
+		{name: "Invalid start", url: "/calendar/week?start=2024-05", wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("bad status code, expected:%v ; actual:%v", tt.wantStatus, w.Code)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var days []CalendarDay
+			if err := json.Unmarshal(w.Body.Bytes(), &days); err != nil {
+				t.Fatalf("unable to unmarshal response: %v", err)
+			}
+			if len(days) != 7 {
+				t.Fatalf("bad number of days: %v", len(days))
+			}
+			for i, d := range days {
+				if want := monday.AddDate(0, 0, i); !d.Day.Equal(want) {
+					t.Errorf("bad day, expected:%v ; actual:%v", want, d.Day)
+				}
+			}
+			if days[2].Name != "Victoire 1945" || !days[2].Ferie || days[2].WorkingDay {
+				t.Errorf("bad calendar day for 8 May: %+v", days[2])
+			}
+			if days[3].Name != "Ascension" {
+				t.Errorf("bad holiday name for Ascension: %+v", days[3])
+			}
+			if days[0].Name != "" || !days[0].WorkingDay {
+				t.Errorf("bad calendar day for working day: %+v", days[0])
+			}
+		})
+	}
+}
+
+func TestCalendarWeekHandler_WeekStart(t *testing.T) {
+	cal := newTestCalendar(t)
+	tests := []struct {
+		name        string
+		sundayFirst bool
+		start       string
+		wantFirst   time.Time
+	}{
+		{name: "Monday first from Wednesday", start: "2024-05-08", wantFirst: time.Date(2024, time.May, 6, 0, 0, 0, 0, cal.Location)},
+		{name: "Monday first from Sunday", start: "2024-05-12", wantFirst: time.Date(2024, time.May, 6, 0, 0, 0, 0, cal.Location)},
+		{name: "Sunday first from Wednesday", sundayFirst: true, start: "2024-05-08", wantFirst: time.Date(2024, time.May, 5, 0, 0, 0, 0, cal.Location)},
+		{name: "Sunday first from Sunday", sundayFirst: true, start: "2024-05-12", wantFirst: time.Date(2024, time.May, 12, 0, 0, 0, 0, cal.Location)},
+		{name: "Sunday first from Saturday", sundayFirst: true, start: "2024-05-11", wantFirst: time.Date(2024, time.May, 5, 0, 0, 0, 0, cal.Location)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &CalendarWeekHandler{cal: newCalendarHolder(cal), sundayFirst: tt.sundayFirst}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/calendar/week?start="+tt.start, nil))
+
+			var days []CalendarDay
+			if err := json.Unmarshal(w.Body.Bytes(), &days); err != nil {
+				t.Fatalf("unable to unmarshal response: %v", err)
+			}
+			if len(days) != 7 {
+				t.Fatalf("bad number of days: %v", len(days))
+			}
+			for i, d := range days {
+				if want := tt.wantFirst.AddDate(0, 0, i); !d.Day.Equal(want) {
+					t.Errorf("bad day, expected:%v ; actual:%v", want, d.Day)
+				}
+			}
+		})
+	}
+}
+
+func TestCalendarRangeHandler_ServeHTTP(t *testing.T) {
+	cal := newTestCalendar(t, calendar.WithCaldav(&MockCaldav{
+		events: []*components.Event{
+			{
+				UID:       "1",
+				DateStart: values.NewDateTime(time.Date(2024, time.May, 10, 0, 0, 0, 0, time.UTC)),
+				DateEnd:   values.NewDateTime(time.Date(2024, time.May, 11, 0, 0, 0, 0, time.UTC)),
+				Summary:   "Holidays",
+			},
+		},
+	}), calendar.WithCaldavSummaryPattern("Holidays"))
+	h := &CalendarRangeHandler{cal: newCalendarHolder(cal)}
+
+	tests := []struct {
+		name       string
+		url        string
+		wantStatus int
+		wantDays   int
+	}{
+		{name: "Month", url: "/calendar/range?start=2024-05-01&end=2024-05-31", wantStatus: http.StatusOK, wantDays: 31},
+		{name: "Single day", url: "/calendar/range?start=2024-05-01&end=2024-05-01", wantStatus: http.StatusOK, wantDays: 1},
+		{name: "Max span", url: "/calendar/range?start=2024-01-01&end=2024-12-31", wantStatus: http.StatusOK, wantDays: 366},
+		{name: "Span too long", url: "/calendar/range?start=2024-01-01&end=2025-01-01", wantStatus: http.StatusBadRequest},
+		{name: "End before start", url: "/calendar/range?start=2024-05-31&end=2024-05-01", wantStatus: http.StatusBadRequest},
+		{name: "Missing end", url: "/calendar/range?start=2024-05-01", wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("bad status code, expected:%v ; actual:%v", tt.wantStatus, w.Code)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var days []CalendarDay
+			if err := json.Unmarshal(w.Body.Bytes(), &days); err != nil {
+				t.Fatalf("unable to unmarshal response: %v", err)
+			}
+			if len(days) != tt.wantDays {
+				t.Errorf("bad number of days, expected:%v ; actual:%v", tt.wantDays, len(days))
+			}
+		})
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/calendar/range?start=2024-05-08&end=2024-05-10", nil))
+	var days []CalendarDay
+	if err := json.Unmarshal(w.Body.Bytes(), &days); err != nil {
+		t.Fatalf("unable to unmarshal response: %v", err)
+	}
+	if days[0].Name != "Victoire 1945" || days[0].Holiday {
+		t.Errorf("bad calendar day for national holiday: %+v", days[0])
+	}
+	if days[2].Name != "Holidays" || !days[2].Holiday {
+		t.Errorf("bad calendar day for caldav holiday: %+v", days[2])
+	}
+}
+
+func TestIsHolidayHandler_Language(t *testing.T) {
+	h := &IsHolidayHandler{cal: newCalendarHolder(newTestCalendar(t, calendar.WithCaldav(&MockCaldav{})))}
+
+	tests := []struct {
+		name           string
+		url            string
+		acceptLanguage string
+		wantStatus     int
+		wantName       string
+	}{
+		{name: "Default", url: "/is-holiday?date=2024-11-01", wantStatus: http.StatusOK, wantName: "Toussaint"},
+		{name: "Parameter", url: "/is-holiday?date=2024-11-01&lang=en", wantStatus: http.StatusOK, wantName: "All Saints' Day"},
+		{name: "Header", url: "/is-holiday?date=2024-11-01", acceptLanguage: "en-US,en;q=0.9", wantStatus: http.StatusOK, wantName: "All Saints' Day"},
+		{name: "Unsupported parameter", url: "/is-holiday?date=2024-11-01&lang=de", wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			if tt.acceptLanguage != "" {
+				r.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("bad status code, expected:%v ; actual:%v", tt.wantStatus, w.Code)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var resp IsHolidayResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("unable to unmarshal response: %v", err)
+			}
+			if resp.Name != tt.wantName {
+				t.Errorf("bad holiday name, expected:%v ; actual:%v", tt.wantName, resp.Name)
+			}
+		})
+	}
+}
+
+func TestWorkingDaysHandler_ServeHTTP(t *testing.T) {
+	cal := newTestCalendar(t)
+	h := &WorkingDaysHandler{cal: newCalendarHolder(cal)}
+
+	tests := []struct {
+		name     string
+		url      string
+		wantCode int
+		want     []time.Time
+	}{
+		{
+			name:     "Holidays and weekend",
+			url:      "/workingdays/list?start=2024-05-07&end=2024-05-14",
+			wantCode: http.StatusOK,
+			want: []time.Time{
+				time.Date(2024, time.May, 7, 0, 0, 0, 0, cal.Location),
+				time.Date(2024, time.May, 10, 0, 0, 0, 0, cal.Location),
+				time.Date(2024, time.May, 13, 0, 0, 0, 0, cal.Location),
+				time.Date(2024, time.May, 14, 0, 0, 0, 0, cal.Location),
+			},
+		},
+		{
+			name:     "Weekend only",
+			url:      "/workingdays/list?start=2024-05-11&end=2024-05-12",
+			wantCode: http.StatusOK,
+			want:     []time.Time{},
+		},
+		{
+			name:     "End before start",
+			url:      "/workingdays/list?start=2024-05-14&end=2024-05-07",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "Missing end",
+			url:      "/workingdays/list?start=2024-05-07",
+			wantCode: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+			if w.Code != tt.wantCode {
+				t.Fatalf("bad status code, expected:%v ; actual:%v", tt.wantCode, w.Code)
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			var days []time.Time
+			if err := json.Unmarshal(w.Body.Bytes(), &days); err != nil {
+				t.Fatalf("unable to unmarshal response: %v", err)
+			}
+			if days == nil || len(days) != len(tt.want) {
+				t.Fatalf("bad working days, expected:%v ; actual:%v", tt.want, days)
+			}
+			for i, d := range days {
+				if !d.Equal(tt.want[i]) {
+					t.Errorf("bad working day, expected:%v ; actual:%v", tt.want[i], d)
+				}
+			}
+		})
+	}
+}
+
+func TestStatsHandler_ServeHTTP(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatalf("unable to load time location: %v", err)
+	}
+	cal := newTestCalendar(t, calendar.WithClock(fixedClock(time.Date(2024, time.May, 6, 10, 0, 0, 0, loc))))
+	h := &StatsHandler{cal: newCalendarHolder(cal)}
+	tests := []struct {
+		name     string
+		url      string
+		wantCode int
+		want     StatsResponse
+	}{
+		{
+			name:     "Leap year",
+			url:      "/stats?year=2024",
+			wantCode: http.StatusOK,
+			want:     StatsResponse{Year: 2024, TotalDays: 366, WeekendDays: 104, Holidays: 11, WorkingDays: 252, BridgeDays: 2},
+		},
+		{
+			name:     "Current year by default",
+			url:      "/stats",
+			wantCode: http.StatusOK,
+			want:     StatsResponse{Year: 2024, TotalDays: 366, WeekendDays: 104, Holidays: 11, WorkingDays: 252, BridgeDays: 2},
+		},
+		{name: "Invalid year", url: "/stats?year=abc", wantCode: http.StatusBadRequest},
+		{name: "Year out of range", url: "/stats?year=1000", wantCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+			if w.Code != tt.wantCode {
+				t.Fatalf("bad status code, expected:%v ; actual:%v", tt.wantCode, w.Code)
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			var got StatsResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatalf("unable to unmarshal response: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("bad stats, expected:%+v ; actual:%+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestHolidaysOfMonthHandler_ServeHTTP(t *testing.T) {
+	cal := newTestCalendar(t,
+		calendar.WithCaldav(&MockCaldav{
+			events: []*components.Event{
+				{
+					UID:       "1",
+					DateStart: values.NewDateTime(time.Date(2024, time.May, 31, 0, 0, 0, 0, time.UTC)),
+					DateEnd:   values.NewDateTime(time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)),
+					Summary:   "Holidays",
+				},
+			},
+		}),
+		calendar.WithCaldavSummaryPattern("Holidays"),
+	)
+	h := &HolidaysOfMonthHandler{cal: newCalendarHolder(cal)}
+
+	tests := []struct {
+		name     string
+		url      string
+		wantCode int
+		want     []string
+	}{
+		{
+			name:     "May",
+			url:      "/holidays/month?year=2024&month=5",
+			wantCode: http.StatusOK,
+			want:     []string{"Fête du travail", "Victoire 1945", "Ascension", "Lundi de Pentecôte", "Holidays"},
+		},
+		{name: "No holiday", url: "/holidays/month?year=2024&month=6", wantCode: http.StatusOK, want: []string{}},
+		{name: "Month too high", url: "/holidays/month?year=2024&month=13", wantCode: http.StatusBadRequest},
+		{name: "Month too low", url: "/holidays/month?year=2024&month=0", wantCode: http.StatusBadRequest},
+		{name: "Missing year", url: "/holidays/month?month=5", wantCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+			if w.Code != tt.wantCode {
+				t.Fatalf("bad status code, expected:%v ; actual:%v", tt.wantCode, w.Code)
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			var holidays []calendar.Holiday
+			if err := json.Unmarshal(w.Body.Bytes(), &holidays); err != nil {
+				t.Fatalf("unable to unmarshal response: %v", err)
+			}
+			if holidays == nil || len(holidays) != len(tt.want) {
+				t.Fatalf("bad holidays, expected:%v ; actual:%v", tt.want, holidays)
+			}
+			for i, name := range tt.want {
+				if holidays[i].Name != name {
+					t.Errorf("bad holiday name, expected:%v ; actual:%v", name, holidays[i].Name)
+				}
+			}
+		})
+	}
+}
+
+func TestUpcomingHolidaysHandler_ServeHTTP(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatalf("unable to load time location: %v", err)
+	}
+	cal := newTestCalendar(t,
+		calendar.WithCaldav(&MockCaldav{
+			events: []*components.Event{
+				{
+					UID:       "1",
+					DateStart: values.NewDateTime(time.Date(2024, time.December, 31, 0, 0, 0, 0, time.UTC)),
+					DateEnd:   values.NewDateTime(time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)),
+					Summary:   "Holidays",
+				},
+			},
+		}),
+		calendar.WithCaldavSummaryPattern("Holidays"),
+		calendar.WithClock(fixedClock(time.Date(2024, time.December, 20, 10, 0, 0, 0, loc))),
+	)
+	h := &UpcomingHolidaysHandler{cal: newCalendarHolder(cal)}
+
+	tests := []struct {
+		name     string
+		url      string
+		wantCode int
+		want     []string
+	}{
+		{name: "Default", url: "/holidays/upcoming", wantCode: http.StatusOK, want: []string{"Noël", "Holidays", "Jour de l'an"}},
+		{
+			name:     "Next year",
+			url:      "/holidays/upcoming?n=4",
+			wantCode: http.StatusOK,
+			want:     []string{"Noël", "Holidays", "Jour de l'an", "Lundi de Pâques"},
+		},
+		{name: "English", url: "/holidays/upcoming?n=1&lang=en", wantCode: http.StatusOK, want: []string{"Christmas Day"}},
+		{name: "Zero", url: "/holidays/upcoming?n=0", wantCode: http.StatusBadRequest},
+		{name: "Too many", url: "/holidays/upcoming?n=51", wantCode: http.StatusBadRequest},
+		{name: "Invalid", url: "/holidays/upcoming?n=abc", wantCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+			if w.Code != tt.wantCode {
+				t.Fatalf("bad status code, expected:%v ; actual:%v", tt.wantCode, w.Code)
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			var holidays []calendar.Holiday
+			if err := json.Unmarshal(w.Body.Bytes(), &holidays); err != nil {
+				t.Fatalf("unable to unmarshal response: %v", err)
+			}
+			if len(holidays) != len(tt.want) {
+				t.Fatalf("bad holidays, expected:%v ; actual:%v", tt.want, holidays)
+			}
+			for i, name := range tt.want {
+				if holidays[i].Name != name {
+					t.Errorf("bad holiday name, expected:%v ; actual:%v", name, holidays[i].Name)
+				}
+			}
+		})
+	}
+}
+
+func TestCalendarRangeHandler_MultiDayEvent(t *testing.T) {
+	// Congés from Monday 8 to Friday 12 April 2024, partially overlapping the range
+	cdav := &CountingCaldav{
+		MockCaldav: MockCaldav{
+			events: []*components.Event{
+				{
+					UID:       "1",
+					DateStart: values.NewDateTime(time.Date(2024, time.April, 8, 0, 0, 0, 0, time.UTC)),
+					DateEnd:   values.NewDateTime(time.Date(2024, time.April, 13, 0, 0, 0, 0, time.UTC)),
+					Summary:   "Congés",
+				},
+			},
+		},
+	}
+	cal := newTestCalendar(t, calendar.WithCaldav(cdav), calendar.WithCaldavSummaryPattern("Congés"))
+
+	tests := []struct {
+		name    string
+		handler http.Handler
+		url     string
+	}{
+		{name: "Range", handler: &CalendarRangeHandler{cal: newCalendarHolder(cal)}, url: "/calendar/range?start=2024-04-11&end=2024-04-14"},
+		{name: "Week", handler: &CalendarWeekHandler{cal: newCalendarHolder(cal)}, url: "/calendar/week?start=2024-04-11"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cdav.queries = 0
+			w := httptest.NewRecorder()
+			tt.handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("bad status code: %v", w.Code)
+			}
+			var days []CalendarDay
+			if err := json.Unmarshal(w.Body.Bytes(), &days); err != nil {
+				t.Fatalf("unable to unmarshal response: %v", err)
+			}
+			for _, d := range days {
+				want := d.Day.Day() >= 8 && d.Day.Day() <= 12
+				if d.Holiday != want || d.Ferie != want {
+					t.Errorf("bad holiday status of %v, expected:%v ; actual:%+v", d.Day, want, d)
+				}
+			}
+			if cdav.queries != 1 {
+				t.Errorf("bad caldav queries count, expected:1 ; actual:%v", cdav.queries)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"domogeek/pkg/calendar"
+	"sync"
+)
+
+// calendarLoader returns the current calendar, see calendarHolder and defaultCalendar
+type calendarLoader interface {
+	Load() *calendar.Calendar
+}
+
+// calendarHolder guards the calendar used by handlers, so that it can be swapped, on configuration reload, while
+// requests are in flight. Requests should load the calendar once and use it until the response is written.
+type calendarHolder struct {
+	mu  sync.RWMutex
+	cal *calendar.Calendar
+}
+
+func newCalendarHolder(cal *calendar.Calendar) *calendarHolder {
+	return &calendarHolder{cal: cal}
+}
+
+// Load returns the current calendar
+func (h *calendarHolder) Load() *calendar.Calendar {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.cal
+}
+
+// Store replaces the calendar, requests in flight keep the previous one
+func (h *calendarHolder) Store(cal *calendar.Calendar) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.cal = cal
+}
+
+// regionHolder guards the region of the routes without region, such as /calendar, so that it can be changed on
+// configuration reload
+type regionHolder struct {
+	mu     sync.RWMutex
+	region calendar.Region
+}
+
+// Load returns the current region
+func (h *regionHolder) Load() calendar.Region {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.region
+}
+
+// Store replaces the region
+func (h *regionHolder) Store(region calendar.Region) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.region = region
+}
+
+// defaultCalendar is the calendar of the current region of a regionHolder, for the status check, metrics and debug
+// routes that follow the -region flag
+type defaultCalendar struct {
+	holders map[calendar.Region]*calendarHolder
+	region  *regionHolder
+}
+
+// Load returns the current calendar of the current region
+func (d *defaultCalendar) Load() *calendar.Calendar {
+	return d.holders[d.region.Load()].Load()
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"domogeek/pkg/calendar"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCalendarHolder_Swap(t *testing.T) {
+	national := newTestCalendar(t)
+	custom := newTestCalendar(t, calendar.WithCustomHolidays([]calendar.CustomHoliday{
+		{Month: time.May, Day: 7, Name: "Congé"},
+	}))
+	holder := newCalendarHolder(national)
+	h := &IsHolidayHandler{cal: holder}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				w := httptest.NewRecorder()
+				h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/is-holiday?date=2024-05-07", nil))
+				var resp IsHolidayResponse
+				if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+					t.Errorf("unable to unmarshal response: %v", err)
+					return
+				}
+				if resp.IsHoliday && resp.Name != "Congé" {
+					t.Errorf("inconsistent response: %+v", resp)
+				}
+			}
+		}()
+	}
+	for i := 0; i < 50; i++ {
+		if i%2 == 0 {
+			holder.Store(custom)
+		} else {
+			holder.Store(national)
+		}
+	}
+	wg.Wait()
+
+	holder.Store(custom)
+	if holder.Load() != custom {
+		t.Errorf("stored calendar should be loaded")
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"domogeek/pkg/calendar"
+	"domogeek/pkg/params"
+	"fmt"
+	"github.com/dolanor/caldav-go/icalendar"
+	"github.com/dolanor/caldav-go/icalendar/properties"
+	"github.com/dolanor/caldav-go/icalendar/values"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+// icsDate is a date value (VALUE=DATE) of an all-day event, values.DateTime only encodes date-times
+type icsDate struct {
+	t time.Time
+}
+
+func (d icsDate) EncodeICalValue() (string, error) {
+	return d.t.Format(values.DateFormatString), nil
+}
+
+func (d icsDate) EncodeICalParams() (properties.Params, error) {
+	return properties.Params{properties.ValuePropertyName: "DATE"}, nil
+}
+
+// icsEvent is the all-day VEVENT of a holiday
+type icsEvent struct {
+	UID       string           `ical:",required"`
+	DateStamp *values.DateTime `ical:"dtstamp,required"`
+	DateStart icsDate          `ical:"dtstart,required"`
+	DateEnd   icsDate          `ical:"dtend,required"`
+	Summary   string           `ical:",omitempty"`
+}
+
+func (e *icsEvent) EncodeICalTag() (string, error) {
+	return "vevent", nil
+}
+
+// icsCalendar is the VCALENDAR of holidays
+type icsCalendar struct {
+	Version   string      `ical:",2.0"`
+	ProductId string      `ical:"prodid,-//domogeek//NONSGML holidays//EN"`
+	Events    []*icsEvent `ical:",omitempty"`
+}
+
+func (c *icsCalendar) EncodeICalTag() (string, error) {
+	return "vcalendar", nil
+}
+
+// newICSCalendar returns the VCALENDAR with an all-day event per holiday, identified by its date
+func newICSCalendar(holidays []calendar.Holiday, stamp time.Time) *icsCalendar {
+	c := icsCalendar{Events: make([]*icsEvent, 0, len(holidays))}
+	for _, h := range holidays {
+		c.Events = append(c.Events, &icsEvent{
+			UID:       fmt.Sprintf("%v@domogeek", h.Date.Format(values.DateFormatString)),
+			DateStamp: values.NewDateTime(stamp.UTC()),
+			DateStart: icsDate{t: h.Date},
+			DateEnd:   icsDate{t: h.Date.AddDate(0, 0, 1)},
+			Summary:   h.Name,
+		})
+	}
+	return &c
+}
+
+// HolidaysICSHandler returns the holidays of the year parameter, national and from CalDAV, as an iCalendar file to
+// subscribe to, current year by default
+type HolidaysICSHandler struct {
+	cal *calendarHolder
+}
+
+func (h *HolidaysICSHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cal := h.cal.Load()
+	year, err := params.OptionalYear(r, "year", cal.Now().In(cal.Location).Year())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	lang, err := requestLanguage(w, r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	holidays := cal.HolidaysOfYear(year)
+	for i := range holidays {
+		holidays[i] = holidays[i].Localized(lang)
+	}
+	content, err := icalendar.Marshal(newICSCalendar(holidays, cal.HolidaysModTime(year)))
+	if err != nil {
+		zap.S().Errorf("unable to marshall holidays of %v: %v", year, err)
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("unable to build calendar"))
+		return
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	if _, err = w.Write([]byte(content + icalendar.Newline)); err != nil {
+		zap.S().Errorf("unable to write response: %v", err)
+	}
+}
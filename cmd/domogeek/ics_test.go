@@ -0,0 +1,94 @@
+package main
+
+import (
+	"domogeek/pkg/calendar"
+	"github.com/dolanor/caldav-go/icalendar"
+	"github.com/dolanor/caldav-go/icalendar/components"
+	"github.com/dolanor/caldav-go/icalendar/values"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHolidaysICSHandler_ServeHTTP(t *testing.T) {
+	cal := newTestCalendar(t,
+		calendar.WithCaldav(&MockCaldav{
+			events: []*components.Event{
+				{
+					UID:       "1",
+					DateStart: values.NewDateTime(time.Date(2024, time.April, 15, 0, 0, 0, 0, time.UTC)),
+					DateEnd:   values.NewDateTime(time.Date(2024, time.April, 16, 0, 0, 0, 0, time.UTC)),
+					Summary:   "Holidays",
+				},
+			},
+		}),
+		calendar.WithCaldavSummaryPattern("Holidays"),
+	)
+	h := &HolidaysICSHandler{cal: newCalendarHolder(cal)}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/holidays.ics?year=2024", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("bad status code, expected:%v ; actual:%v", http.StatusOK, w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/calendar") {
+		t.Errorf("bad content type, expected:text/calendar ; actual:%v", ct)
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, "DTSTART;VALUE=DATE:20241225\r\n") || !strings.Contains(body, "DTEND;VALUE=DATE:20241226\r\n") {
+		t.Errorf("holidays should be all-day events: %v", body)
+	}
+
+	var parsed components.Calendar
+	if err := icalendar.Unmarshal(body, &parsed); err != nil {
+		t.Fatalf("unable to parse calendar: %v\n%v", err, body)
+	}
+	if len(parsed.Events) != 12 {
+		t.Fatalf("bad events count, expected 11 national and 1 caldav holidays ; actual:%v", len(parsed.Events))
+	}
+	uids := make(map[string]bool, len(parsed.Events))
+	for _, evt := range parsed.Events {
+		uids[evt.UID] = true
+	}
+	if len(uids) != len(parsed.Events) {
+		t.Errorf("UIDs should be unique: %v", uids)
+	}
+
+	tests := []struct {
+		uid     string
+		summary string
+		start   time.Time
+	}{
+		{"20240401@domogeek", "Lundi de Pâques", time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{"20240415@domogeek", "Holidays", time.Date(2024, time.April, 15, 0, 0, 0, 0, time.UTC)},
+		{"20241225@domogeek", "Noël", time.Date(2024, time.December, 25, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.uid, func(t *testing.T) {
+			for _, evt := range parsed.Events {
+				if evt.UID != tt.uid {
+					continue
+				}
+				if evt.Summary != tt.summary {
+					t.Errorf("bad summary, expected:%v ; actual:%v", tt.summary, evt.Summary)
+				}
+				if !evt.DateStart.NativeTime().Equal(tt.start) {
+					t.Errorf("bad start, expected:%v ; actual:%v", tt.start, evt.DateStart.NativeTime())
+				}
+				if !evt.DateEnd.NativeTime().Equal(tt.start.AddDate(0, 0, 1)) {
+					t.Errorf("bad end, expected:%v ; actual:%v", tt.start.AddDate(0, 0, 1), evt.DateEnd.NativeTime())
+				}
+				return
+			}
+			t.Errorf("missing event %v", tt.uid)
+		})
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/holidays.ics?year=abc", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("bad status code for invalid year, expected:%v ; actual:%v", http.StatusBadRequest, w.Code)
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"domogeek/pkg/calendar"
+	"errors"
+	"fmt"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+)
+
+// metrics are the calendar prometheus metrics
+type metrics struct {
+	requests    *prometheus.CounterVec
+	summary     *prometheus.SummaryVec
+	histogram   *prometheus.HistogramVec
+	nextHoliday prometheus.Gauge
+	rateLimited prometheus.Counter
+}
+
+// newMetrics creates the calendar metrics, named namespace_subsystem_*, and registers them with registry. Metrics
+// already registered are reused, so that several handlers can share a registry.
+func newMetrics(registry *prometheus.Registry, namespace, subsystem string) *metrics {
+	return &metrics{
+		requests: register(registry, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "request_total",
+			Help:      "Total request to calendar service",
+		},
+			[]string{
+				"code",
+				"method",
+				"handler",
+				"region",
+			})).(*prometheus.CounterVec),
+		summary: register(registry, prometheus.NewSummaryVec(prometheus.SummaryOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "summary",
+			Help:      "Calendar request summary",
+		},
+			[]string{
+				"handler",
+				"region",
+			})).(*prometheus.SummaryVec),
+		histogram: register(registry, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "histogram",
+			Help:      "Request duration histogram",
+		},
+			[]string{
+				"handler",
+				"region",
+			})).(*prometheus.HistogramVec),
+		nextHoliday: register(registry, prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "days_until_next_holiday",
+			Help:      "Number of days until the next holiday",
+		})).(prometheus.Gauge),
+		rateLimited: register(registry, prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "caldav_rate_limited_total",
+			Help:      "Total 429 Too Many Requests responses of the caldav server",
+		})).(prometheus.Counter),
+	}
+}
+
+// register registers collector with registry and returns it, or returns the collector already registered
+func register(registry *prometheus.Registry, collector prometheus.Collector) prometheus.Collector {
+	if err := registry.Register(collector); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if errors.As(err, &are) {
+			return are.ExistingCollector
+		}
+		zap.S().Panicf("unable to register metric: %v", err)
+	}
+	return collector
+}
+
+// instrument wraps handler with the calendar prometheus metrics, labeled with the name of the handler and the region
+func (m *metrics) instrument(name string, region calendar.Region, handler http.Handler) http.Handler {
+	labels := prometheus.Labels{"handler": name, "region": string(region)}
+	return promhttp.InstrumentHandlerDuration(
+		m.histogram.MustCurryWith(labels),
+		promhttp.InstrumentHandlerDuration(
+			m.summary.MustCurryWith(labels),
+			promhttp.InstrumentHandlerCounter(
+				m.requests.MustCurryWith(labels),
+				handler)))
+}
+
+// route wraps handler with middlewares, the first one being the outermost, then with the metrics, so that the
+// responses of the middlewares, such as timeouts, are counted too
+func (m *metrics) route(name string, region calendar.Region, handler http.Handler, middlewares ...middleware) http.Handler {
+	return m.instrument(name, region, chain(handler, middlewares...))
+}
+
+// validateMetricsPath returns an error if path, of metrics, isn't absolute or collides with one of the route patterns:
+// equal to a pattern, or within a pattern ending with a slash, such as the dates and regions of /calendar/
+func validateMetricsPath(path string, patterns []string) error {
+	if !strings.HasPrefix(path, "/") {
+		return fmt.Errorf("'%v' must start with /", path)
+	}
+	for _, pattern := range patterns {
+		if path == pattern || (strings.HasSuffix(pattern, "/") && strings.HasPrefix(path, pattern)) {
+			return fmt.Errorf("'%v' collides with route '%v'", path, pattern)
+		}
+	}
+	return nil
+}
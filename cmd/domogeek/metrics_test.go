@@ -0,0 +1,114 @@
+package main
+
+import (
+	"domogeek/pkg/calendar"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	m := newMetrics(registry, "home", "days")
+
+	handler := m.instrument("/calendar", calendar.RegionMetropole, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/calendar", nil))
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("unable to gather metrics: %v", err)
+	}
+	names := make(map[string]bool, len(families))
+	for _, f := range families {
+		names[f.GetName()] = true
+	}
+	for _, name := range []string{"home_days_request_total", "home_days_summary", "home_days_histogram", "home_days_days_until_next_holiday", "home_days_caldav_rate_limited_total"} {
+		if !names[name] {
+			t.Errorf("metric %v not registered, got %v", name, names)
+		}
+	}
+}
+
+func TestNewMetrics_SharedRegistry(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	first := newMetrics(registry, "domogeek", "calendar")
+	second := newMetrics(registry, "domogeek", "calendar")
+
+	for _, m := range []*metrics{first, second} {
+		handler := m.instrument("/calendar", calendar.RegionMetropole, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/calendar", nil))
+	}
+
+	if first.requests != second.requests {
+		t.Errorf("metrics registered twice should be shared")
+	}
+	if got := testutil.ToFloat64(first.requests.WithLabelValues("200", "get", "/calendar", "metropole")); got != 2 {
+		t.Errorf("bad request count, expected:%v ; actual:%v", 2, got)
+	}
+}
+
+func TestMetrics_Labels(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	m := newMetrics(registry, "domogeek", "calendar")
+	ok := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {})
+
+	m.instrument("/calendar/{region}", calendar.RegionAlsaceMoselle, ok).
+		ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/calendar/alsace-moselle", nil))
+	m.instrument("/holidays", calendar.RegionMetropole, ok).
+		ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/holidays", nil))
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("unable to gather metrics: %v", err)
+	}
+	for _, f := range families {
+		if f.GetName() != "domogeek_calendar_histogram" {
+			continue
+		}
+		got := make(map[string]string)
+		for _, metric := range f.GetMetric() {
+			labels := make(map[string]string)
+			for _, l := range metric.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			got[labels["handler"]] = labels["region"]
+		}
+		want := map[string]string{"/calendar/{region}": "alsace-moselle", "/holidays": "metropole"}
+		if len(got) != len(want) {
+			t.Errorf("bad histogram labels, expected:%v ; actual:%v", want, got)
+		}
+		for handler, region := range want {
+			if got[handler] != region {
+				t.Errorf("bad region label of handler %v, expected:%v ; actual:%v", handler, region, got[handler])
+			}
+		}
+		return
+	}
+	t.Error("histogram not registered")
+}
+
+func TestValidateMetricsPath(t *testing.T) {
+	patterns := []string{"/calendar", "/calendar/", "/calendar/week", "/holidays", "/healthz", "/status"}
+	tests := []struct {
+		path    string
+		wantErr bool
+	}{
+		{path: "/metrics"},
+		{path: "/internal/metrics"},
+		{path: "/holidays/metrics"},
+		{path: "metrics", wantErr: true},
+		{path: "/calendar", wantErr: true},
+		{path: "/calendar/metrics", wantErr: true},
+		{path: "/status", wantErr: true},
+		{path: "/healthz", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if err := validateMetricsPath(tt.path, patterns); (err != nil) != tt.wantErr {
+				t.Errorf("validateMetricsPath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
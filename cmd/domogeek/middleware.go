@@ -0,0 +1,311 @@
+package main
+
+import (
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type middleware func(http.Handler) http.Handler
+
+// chain wraps handler with middlewares, the first one being the outermost
+func chain(handler http.Handler, middlewares ...middleware) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// statusRecorder captures the status code written by the wrapped handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	if r.status == 0 {
+		r.status = code
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+const requestIdHeader = "X-Request-Id"
+
+func newRequestId() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		zap.S().Warnf("unable to generate request id: %v", err)
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// clientIP returns the IP of the client of r. Behind a trusted reverse proxy, the IP is read from the X-Forwarded-For
+// header, its first entry being the original client, or from X-Real-IP. These headers are set by clients as well, so
+// they are ignored unless trustProxy is set.
+func clientIP(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			return strings.TrimSpace(strings.Split(forwarded, ",")[0])
+		}
+		if realIP := strings.TrimSpace(r.Header.Get("X-Real-IP")); realIP != "" {
+			return realIP
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// accessLog logs each request at the given level with a request id echoed in the X-Request-Id response header, and
+// the client IP, see clientIP
+func accessLog(level zapcore.Level, trustProxy bool) middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestId := r.Header.Get(requestIdHeader)
+			if requestId == "" {
+				requestId = newRequestId()
+			}
+			w.Header().Set(requestIdHeader, requestId)
+
+			start := time.Now()
+			rec := &statusRecorder{ResponseWriter: w}
+			next.ServeHTTP(rec, r)
+			if rec.status == 0 {
+				rec.status = http.StatusOK
+			}
+
+			if ce := zap.L().Check(level, "access"); ce != nil {
+				ce.Write(
+					zap.String("request_id", requestId),
+					zap.String("client_ip", clientIP(r, trustProxy)),
+					zap.String("method", r.Method),
+					zap.String("path", r.URL.Path),
+					zap.Int("status", rec.status),
+					zap.Duration("duration", time.Since(start)),
+				)
+			}
+		})
+	}
+}
+
+// cors emits CORS headers for the allowed origins, a comma separated list or `*` for any origin, and answers
+// preflight requests
+func cors(allowedOrigins string) middleware {
+	origins := make(map[string]bool)
+	for _, o := range strings.Split(allowedOrigins, ",") {
+		origins[strings.TrimSpace(o)] = true
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			switch {
+			case origins["*"]:
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			case origin != "" && origins[origin]:
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+				w.Header().Add("Vary", "Origin")
+			}
+
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				w.Header().Set("Access-Control-Allow-Methods", http.MethodGet)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// allowMethods replies 405 Method Not Allowed, with the Allow header, to requests whose method isn't one of methods
+func allowMethods(methods ...string) middleware {
+	allowed := make(map[string]bool, len(methods))
+	for _, m := range methods {
+		allowed[m] = true
+	}
+	allow := strings.Join(methods, ", ")
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !allowed[r.Method] {
+				w.Header().Set("Allow", allow)
+				writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %v not allowed", r.Method))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// timeoutResponseWriter marks the 503 response of http.TimeoutHandler as JSON
+type timeoutResponseWriter struct {
+	http.ResponseWriter
+}
+
+func (t *timeoutResponseWriter) WriteHeader(code int) {
+	if code == http.StatusServiceUnavailable && t.Header().Get("Content-Type") == "" {
+		t.Header().Set("Content-Type", "application/json")
+	}
+	t.ResponseWriter.WriteHeader(code)
+}
+
+// timeout replies 503 Service Unavailable with a JSON error to requests not handled within d, such as when CalDAV is
+// slow. The request context is canceled on timeout.
+func timeout(d time.Duration) middleware {
+	body, err := json.Marshal(ErrorResponse{Error: fmt.Sprintf("request not handled within %v", d)})
+	if err != nil {
+		zap.S().Errorf("unable to marshall timeout error: %v", err)
+	}
+	return func(next http.Handler) http.Handler {
+		th := http.TimeoutHandler(next, d, string(body))
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			th.ServeHTTP(&timeoutResponseWriter{ResponseWriter: w}, r)
+		})
+	}
+}
+
+// headResponseWriter discards the body and delays the status until the body length is known
+type headResponseWriter struct {
+	http.ResponseWriter
+	status int
+	length int
+}
+
+func (h *headResponseWriter) WriteHeader(code int) {
+	if h.status == 0 {
+		h.status = code
+	}
+}
+
+func (h *headResponseWriter) Write(b []byte) (int, error) {
+	if h.status == 0 {
+		h.status = http.StatusOK
+	}
+	h.length += len(b)
+	return len(b), nil
+}
+
+// head answers HEAD requests as GET ones, with the same headers and the Content-Length of the body, but no body
+func head(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		hw := &headResponseWriter{ResponseWriter: w}
+		get := r.Clone(r.Context())
+		get.Method = http.MethodGet
+		next.ServeHTTP(hw, get)
+		if hw.status == 0 {
+			hw.status = http.StatusOK
+		}
+		if hw.length > 0 && w.Header().Get("Content-Length") == "" {
+			w.Header().Set("Content-Length", strconv.Itoa(hw.length))
+		}
+		w.WriteHeader(hw.status)
+	})
+}
+
+// gzipMinSize is the minimal body size to compress, smaller bodies are sent as is to avoid the gzip overhead
+const gzipMinSize = 1024
+
+// gzipResponseWriter buffers the body until gzipMinSize bytes are written, then compresses the remaining response
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	status int
+	buf    []byte
+	gz     *gzip.Writer
+}
+
+func (g *gzipResponseWriter) WriteHeader(code int) {
+	if g.status == 0 {
+		g.status = code
+	}
+}
+
+func (g *gzipResponseWriter) Write(b []byte) (int, error) {
+	if g.status == 0 {
+		g.status = http.StatusOK
+	}
+	if g.gz != nil {
+		return g.gz.Write(b)
+	}
+
+	g.buf = append(g.buf, b...)
+	if len(g.buf) < gzipMinSize {
+		return len(b), nil
+	}
+
+	h := g.Header()
+	if h.Get("Content-Type") == "" {
+		h.Set("Content-Type", http.DetectContentType(g.buf))
+	}
+	h.Set("Content-Encoding", "gzip")
+	h.Del("Content-Length")
+	g.ResponseWriter.WriteHeader(g.status)
+	g.gz = gzip.NewWriter(g.ResponseWriter)
+	if _, err := g.gz.Write(g.buf); err != nil {
+		return 0, err
+	}
+	g.buf = nil
+	return len(b), nil
+}
+
+// Close flushes the compressed stream or the buffered uncompressed body
+func (g *gzipResponseWriter) Close() error {
+	if g.gz != nil {
+		return g.gz.Close()
+	}
+	if g.status == 0 {
+		return nil
+	}
+	g.ResponseWriter.WriteHeader(g.status)
+	_, err := g.ResponseWriter.Write(g.buf)
+	return err
+}
+
+// compress gzips response bodies of at least gzipMinSize bytes for clients accepting gzip encoding. It should be
+// wrapped by the prometheus instrumentation so that written bytes are the compressed ones.
+func compress(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		if !acceptsGzip(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		gw := &gzipResponseWriter{ResponseWriter: w}
+		defer func() {
+			if err := gw.Close(); err != nil {
+				zap.S().Errorf("unable to write compressed response: %v", err)
+			}
+		}()
+		next.ServeHTTP(gw, r)
+	})
+}
+
+func acceptsGzip(r *http.Request) bool {
+	for _, encoding := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		if strings.TrimSpace(strings.SplitN(encoding, ";", 2)[0]) == "gzip" {
+			return true
+		}
+	}
+	return false
+}
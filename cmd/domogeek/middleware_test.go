@@ -0,0 +1,329 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"domogeek/pkg/calendar"
+	"encoding/json"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompress(t *testing.T) {
+	large := strings.Repeat("holiday ", gzipMinSize)
+	tests := []struct {
+		name           string
+		body           string
+		acceptEncoding string
+		wantGzip       bool
+	}{
+		{"Large body", large, "gzip, deflate", true},
+		{"Small body", "{}", "gzip", false},
+		{"Gzip not accepted", large, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := compress(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/plain")
+				_, _ = io.WriteString(w, tt.body)
+			}))
+			r := httptest.NewRequest(http.MethodGet, "/holidays", nil)
+			if tt.acceptEncoding != "" {
+				r.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != http.StatusOK {
+				t.Errorf("bad status code: %v", w.Code)
+			}
+			gzipped := w.Header().Get("Content-Encoding") == "gzip"
+			if gzipped != tt.wantGzip {
+				t.Errorf("gzip encoding = %v, want %v", gzipped, tt.wantGzip)
+			}
+			var body io.Reader = w.Body
+			if gzipped {
+				gz, err := gzip.NewReader(w.Body)
+				if err != nil {
+					t.Fatalf("unable to read gzip body: %v", err)
+				}
+				body = gz
+			}
+			content, err := io.ReadAll(body)
+			if err != nil {
+				t.Fatalf("unable to read body: %v", err)
+			}
+			if string(content) != tt.body {
+				t.Errorf("bad body, %d bytes but %d are expected", len(content), len(tt.body))
+			}
+		})
+	}
+}
+
+func TestAllowMethods(t *testing.T) {
+	h := allowMethods(http.MethodGet)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "{}")
+	}))
+	tests := []struct {
+		method     string
+		wantStatus int
+		wantAllow  string
+	}{
+		{http.MethodGet, http.StatusOK, ""},
+		{http.MethodPost, http.StatusMethodNotAllowed, "GET"},
+		{http.MethodDelete, http.StatusMethodNotAllowed, "GET"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(tt.method, "/calendar", nil))
+			if w.Code != tt.wantStatus {
+				t.Errorf("bad status code, expected:%v ; actual:%v", tt.wantStatus, w.Code)
+			}
+			if allow := w.Header().Get("Allow"); allow != tt.wantAllow {
+				t.Errorf("bad Allow header, expected:%v ; actual:%v", tt.wantAllow, allow)
+			}
+		})
+	}
+}
+
+func TestAllowMethods_Metrics(t *testing.T) {
+	m := newMetrics(prometheus.NewRegistry(), "domogeek", "calendar")
+	h := m.route("/calendar", calendar.RegionMetropole, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "{}")
+	}), allowMethods(http.MethodGet, http.MethodHead), head)
+
+	for _, method := range []string{http.MethodGet, http.MethodHead, http.MethodPost} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, "/calendar", nil))
+	}
+
+	tests := []struct {
+		code   string
+		method string
+	}{
+		{"200", "get"},
+		{"200", "head"},
+		{"405", "post"},
+	}
+	for _, tt := range tests {
+		if got := testutil.ToFloat64(m.requests.WithLabelValues(tt.code, tt.method, "/calendar", "metropole")); got != 1 {
+			t.Errorf("bad %v %v request count, expected:1 ; actual:%v", tt.code, tt.method, got)
+		}
+	}
+}
+
+func TestCors(t *testing.T) {
+	tests := []struct {
+		name          string
+		allowed       string
+		method        string
+		origin        string
+		requestMethod string
+		wantStatus    int
+		wantAllowed   string
+		wantVary      string
+		wantMethods   string
+	}{
+		{
+			name: "Allowed origin", allowed: "https://a.example, https://b.example", method: http.MethodGet,
+			origin: "https://b.example", wantStatus: http.StatusOK, wantAllowed: "https://b.example", wantVary: "Origin",
+		},
+		{
+			name: "Any origin", allowed: "*", method: http.MethodGet,
+			origin: "https://c.example", wantStatus: http.StatusOK, wantAllowed: "*",
+		},
+		{
+			name: "Disallowed origin", allowed: "https://a.example", method: http.MethodGet,
+			origin: "https://evil.example", wantStatus: http.StatusOK,
+		},
+		{
+			name: "Preflight", allowed: "https://a.example", method: http.MethodOptions, origin: "https://a.example",
+			requestMethod: http.MethodGet, wantStatus: http.StatusNoContent, wantAllowed: "https://a.example",
+			wantVary: "Origin", wantMethods: http.MethodGet,
+		},
+		{
+			name: "Preflight of disallowed origin", allowed: "https://a.example", method: http.MethodOptions,
+			origin: "https://evil.example", requestMethod: http.MethodGet, wantStatus: http.StatusNoContent,
+			wantMethods: http.MethodGet,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := cors(tt.allowed)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = io.WriteString(w, "{}")
+			}))
+			r := httptest.NewRequest(tt.method, "/calendar", nil)
+			r.Header.Set("Origin", tt.origin)
+			if tt.requestMethod != "" {
+				r.Header.Set("Access-Control-Request-Method", tt.requestMethod)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("bad status code, expected:%v ; actual:%v", tt.wantStatus, w.Code)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.wantAllowed {
+				t.Errorf("bad Access-Control-Allow-Origin header, expected:%v ; actual:%v", tt.wantAllowed, got)
+			}
+			if got := w.Header().Get("Vary"); got != tt.wantVary {
+				t.Errorf("bad Vary header, expected:%v ; actual:%v", tt.wantVary, got)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Methods"); got != tt.wantMethods {
+				t.Errorf("bad Access-Control-Allow-Methods header, expected:%v ; actual:%v", tt.wantMethods, got)
+			}
+		})
+	}
+}
+
+func TestHead(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatalf("unable to load time location: %v", err)
+	}
+	cal := newTestCalendar(t, calendar.WithCaldav(&MockCaldav{}), calendar.WithClock(fixedClock(time.Date(2024, time.May, 8, 10, 0, 0, 0, loc))))
+	h := chain(compress(&CalendarHandler{cal: newCalendarHolder(cal)}), head)
+
+	get := httptest.NewRecorder()
+	h.ServeHTTP(get, httptest.NewRequest(http.MethodGet, "/calendar", nil))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/calendar", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("bad status code: %v", w.Code)
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("HEAD response should not have a body: %v", w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("bad Content-Type, expected:application/json ; actual:%v", ct)
+	}
+	if cl := w.Header().Get("Content-Length"); cl != strconv.Itoa(get.Body.Len()) {
+		t.Errorf("bad Content-Length, expected:%v ; actual:%v", get.Body.Len(), cl)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	h := timeout(10 * time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, "{}")
+	}))
+
+	t.Run("Slow handler", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/slow", nil))
+		if w.Code != http.StatusServiceUnavailable {
+			t.Errorf("bad status code, expected:%v ; actual:%v", http.StatusServiceUnavailable, w.Code)
+		}
+		if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+			t.Errorf("bad content type: %v", ct)
+		}
+		var resp ErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("unable to unmarshal response %q: %v", w.Body.String(), err)
+		}
+		if resp.Error != "request not handled within 10ms" {
+			t.Errorf("bad error: %v", resp.Error)
+		}
+	})
+	t.Run("Fast handler", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/fast", nil))
+		if w.Code != http.StatusOK {
+			t.Errorf("bad status code, expected:%v ; actual:%v", http.StatusOK, w.Code)
+		}
+		if body := w.Body.String(); body != "{}" {
+			t.Errorf("bad body: %v", body)
+		}
+	})
+}
+
+func TestTimeout_Metrics(t *testing.T) {
+	m := newMetrics(prometheus.NewRegistry(), "domogeek", "calendar")
+	h := m.route("/calendar", calendar.RegionMetropole, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		_, _ = io.WriteString(w, "{}")
+	}), timeout(10*time.Millisecond))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/calendar", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("bad status code, expected:%v ; actual:%v", http.StatusServiceUnavailable, w.Code)
+	}
+	if got := testutil.ToFloat64(m.requests.WithLabelValues("503", "get", "/calendar", "metropole")); got != 1 {
+		t.Errorf("timed out request should be counted, expected:1 ; actual:%v", got)
+	}
+	if got := testutil.CollectAndCount(m.requests); got != 1 {
+		t.Errorf("only the timeout should be counted, expected 1 sample ; actual:%v", got)
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string]string
+		trustProxy bool
+		want       string
+	}{
+		{name: "Direct", remoteAddr: "192.0.2.1:1234", want: "192.0.2.1"},
+		{name: "Direct IPv6", remoteAddr: "[2001:db8::1]:1234", want: "2001:db8::1"},
+		{name: "Untrusted forwarded for", remoteAddr: "192.0.2.1:1234", headers: map[string]string{"X-Forwarded-For": "203.0.113.7"}, want: "192.0.2.1"},
+		{name: "Untrusted real IP", remoteAddr: "192.0.2.1:1234", headers: map[string]string{"X-Real-IP": "203.0.113.7"}, want: "192.0.2.1"},
+		{name: "Trusted forwarded for", remoteAddr: "192.0.2.1:1234", headers: map[string]string{"X-Forwarded-For": "203.0.113.7, 198.51.100.2"}, trustProxy: true, want: "203.0.113.7"},
+		{name: "Trusted real IP", remoteAddr: "192.0.2.1:1234", headers: map[string]string{"X-Real-IP": "203.0.113.7"}, trustProxy: true, want: "203.0.113.7"},
+		{name: "Trusted without headers", remoteAddr: "192.0.2.1:1234", trustProxy: true, want: "192.0.2.1"},
+		{name: "Unix socket", remoteAddr: "@", want: "@"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/calendar", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+			if got := clientIP(r, tt.trustProxy); got != tt.want {
+				t.Errorf("bad client IP, expected:%v ; actual:%v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAccessLog_ClientIP(t *testing.T) {
+	var logs bytes.Buffer
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), zapcore.AddSync(&logs), zap.DebugLevel)
+	defer zap.ReplaceGlobals(zap.New(core))()
+
+	for _, trustProxy := range []bool{false, true} {
+		logs.Reset()
+		h := chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}), accessLog(zap.InfoLevel, trustProxy))
+		r := httptest.NewRequest(http.MethodGet, "/calendar", nil)
+		r.RemoteAddr = "192.0.2.1:1234"
+		r.Header.Set("X-Forwarded-For", "203.0.113.7")
+		h.ServeHTTP(httptest.NewRecorder(), r)
+
+		want := `"client_ip":"192.0.2.1"`
+		if trustProxy {
+			want = `"client_ip":"203.0.113.7"`
+		}
+		if !strings.Contains(logs.String(), want) {
+			t.Errorf("access log should contain %v with trust proxy %v: %v", want, trustProxy, logs.String())
+		}
+	}
+}
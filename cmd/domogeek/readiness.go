@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/zap"
+	"sync/atomic"
+	"time"
+)
+
+// warmUpDays is the number of days, from today, read from CalDAV on warm-up to populate the cache
+const warmUpDays = 31
+
+// warmUpRetry is the delay between warm-up attempts while CalDAV can't be queried
+const warmUpRetry = 5 * time.Second
+
+var errWarmingUp = errors.New("waiting for the first successful caldav query")
+
+// readiness is set once the service is warm: after the first successful CalDAV query, or at once without CalDAV
+type readiness struct {
+	ready int32
+}
+
+func (r *readiness) setReady() {
+	atomic.StoreInt32(&r.ready, 1)
+}
+
+func (r *readiness) isReady() bool {
+	return atomic.LoadInt32(&r.ready) == 1
+}
+
+// check is the health check of readiness, failing until the service is warm
+func (r *readiness) check(_ context.Context) error {
+	if !r.isReady() {
+		return errWarmingUp
+	}
+	return nil
+}
+
+// warmUp reads the CalDAV holidays of the coming warmUpDays, retrying every interval until a query succeeds, then
+// sets r ready. It returns when ctx is done.
+func warmUp(ctx context.Context, holder calendarLoader, interval time.Duration, r *readiness) {
+	for {
+		cal := holder.Load()
+		if !cal.HasCaldav() {
+			r.setReady()
+			return
+		}
+		today := cal.Now().In(cal.Location)
+		_, err := cal.CaldavHolidayDays(today, today.AddDate(0, 0, warmUpDays-1))
+		if err == nil {
+			zap.S().Infof("caldav warmed up, service ready")
+			r.setReady()
+			return
+		}
+		zap.S().Warnf("unable to warm up caldav, retry in %v: %v", interval, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
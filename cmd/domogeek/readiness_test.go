@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"domogeek/pkg/calendar"
+	"errors"
+	"github.com/dolanor/caldav-go/caldav/entities"
+	"github.com/dolanor/caldav-go/icalendar/components"
+	"testing"
+	"time"
+)
+
+// FailingCaldav fails the first failures queries, then returns no events
+type FailingCaldav struct {
+	failures int
+	queries  int
+}
+
+func (m *FailingCaldav) QueryEvents(_ string, _ *entities.CalendarQuery) ([]*components.Event, error) {
+	m.queries++
+	if m.queries <= m.failures {
+		return nil, errors.New("connection refused")
+	}
+	return nil, nil
+}
+
+func TestWarmUp(t *testing.T) {
+	cdav := &FailingCaldav{failures: 2}
+	cal := newTestCalendar(t, calendar.WithCaldav(cdav), calendar.WithCaldavSummaryPattern("Holidays"))
+	r := &readiness{}
+	if err := r.check(context.Background()); !errors.Is(err, errWarmingUp) {
+		t.Errorf("readiness should fail before warm-up, actual:%v", err)
+	}
+
+	warmUp(context.Background(), newCalendarHolder(cal), time.Millisecond, r)
+	if err := r.check(context.Background()); err != nil {
+		t.Errorf("readiness should succeed after warm-up: %v", err)
+	}
+	if cdav.queries != cdav.failures+1 {
+		t.Errorf("bad queries count, expected:%v ; actual:%v", cdav.failures+1, cdav.queries)
+	}
+}
+
+func TestWarmUp_WithoutCaldav(t *testing.T) {
+	r := &readiness{}
+	warmUp(context.Background(), newCalendarHolder(newTestCalendar(t)), time.Hour, r)
+	if !r.isReady() {
+		t.Error("readiness should be set at once without caldav")
+	}
+}
+
+func TestWarmUp_Canceled(t *testing.T) {
+	cdav := &FailingCaldav{failures: 1}
+	cal := newTestCalendar(t, calendar.WithCaldav(cdav), calendar.WithCaldavSummaryPattern("Holidays"))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r := &readiness{}
+	warmUp(ctx, newCalendarHolder(cal), time.Hour, r)
+	if r.isReady() {
+		t.Error("readiness should not be set while caldav fails")
+	}
+}
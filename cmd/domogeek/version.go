@@ -0,0 +1,32 @@
+package main
+
+import (
+	"net/http"
+	"runtime"
+)
+
+// Build metadata, set with -ldflags "-X main.version=... -X main.commit=... -X main.buildDate=..."
+var (
+	version   = "dev"
+	commit    = "unknown"
+	buildDate = "unknown"
+)
+
+type VersionResponse struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildDate string `json:"build_date"`
+	GoVersion string `json:"go_version"`
+}
+
+// VersionHandler returns the build metadata of the running binary
+type VersionHandler struct{}
+
+func (h *VersionHandler) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, VersionResponse{
+		Version:   version,
+		Commit:    commit,
+		BuildDate: buildDate,
+		GoVersion: runtime.Version(),
+	})
+}
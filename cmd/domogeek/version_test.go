@@ -0,0 +1,30 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+)
+
+func TestVersionHandler_ServeHTTP(t *testing.T) {
+	defer func(v, c, d string) {
+		version, commit, buildDate = v, c, d
+	}(version, commit, buildDate)
+	version, commit, buildDate = "v1.2.3", "0873930", "2024-05-07T10:00:00Z"
+
+	w := httptest.NewRecorder()
+	(&VersionHandler{}).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/version", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("bad status code: %v", w.Code)
+	}
+	var resp VersionResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unable to unmarshal response: %v", err)
+	}
+	want := VersionResponse{Version: "v1.2.3", Commit: "0873930", BuildDate: "2024-05-07T10:00:00Z", GoVersion: runtime.Version()}
+	if resp != want {
+		t.Errorf("bad response, expected:%+v ; actual:%+v", want, resp)
+	}
+}
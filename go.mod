@@ -8,6 +8,7 @@ require (
 	github.com/hellofresh/health-go/v4 v4.5.0
 	github.com/prometheus/client_golang v1.12.1
 	go.uber.org/zap v1.21.0
+	golang.org/x/text v0.14.0
 )
 
 require (
@@ -22,6 +23,6 @@ require (
 	go.opentelemetry.io/otel/trace v1.0.0 // indirect
 	go.uber.org/atomic v1.7.0 // indirect
 	go.uber.org/multierr v1.6.0 // indirect
-	golang.org/x/sys v0.0.0-20220114195835-da31bd327af9 // indirect
+	golang.org/x/sys v0.5.0 // indirect
 	google.golang.org/protobuf v1.26.0 // indirect
 )
@@ -0,0 +1,285 @@
+package calendar
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// CacheStats describes the content of the calendar caches and their hit/miss counts
+type CacheStats struct {
+	Years         []int  `json:"years"`
+	HolidayHits   uint64 `json:"holiday_hits"`
+	HolidayMisses uint64 `json:"holiday_misses"`
+	CaldavSize    int    `json:"caldav_size"`
+	CaldavHits    uint64 `json:"caldav_hits"`
+	CaldavMisses  uint64 `json:"caldav_misses"`
+}
+
+type yearEntry struct {
+	holidays []Holiday
+	computed time.Time
+	// days are the sorted days of holidays, once filtered and observed, computed on first lookup
+	days []time.Time
+}
+
+// holidayCache keeps computed holidays per year
+type holidayCache struct {
+	mu     sync.Mutex
+	years  map[int]yearEntry
+	hits   uint64
+	misses uint64
+}
+
+func newHolidayCache() *holidayCache {
+	return &holidayCache{years: make(map[int]yearEntry)}
+}
+
+// get returns a copy of the holidays of the year, computed on cache miss
+func (c *holidayCache) get(year int, compute func(year int) []Holiday) []Holiday {
+	entry := c.entry(year, compute)
+	holidays := make([]Holiday, len(entry.holidays))
+	copy(holidays, entry.holidays)
+	return holidays
+}
+
+func (c *holidayCache) entry(year int, compute func(year int) []Holiday) yearEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lookup(year, compute)
+}
+
+// days returns the sorted holiday days of the year, built from the holidays with toDays on first lookup. The
+// returned slice is shared, it must not be modified.
+func (c *holidayCache) days(year int, compute func(year int) []Holiday, toDays func([]Holiday) []time.Time) []time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := c.lookup(year, compute)
+	if entry.days == nil {
+		entry.days = toDays(entry.holidays)
+		c.years[year] = entry
+	}
+	return entry.days
+}
+
+// lookup returns the entry of the year, computed on cache miss. c.mu must be held.
+func (c *holidayCache) lookup(year int, compute func(year int) []Holiday) yearEntry {
+	entry, ok := c.years[year]
+	if ok {
+		c.hits++
+	} else {
+		c.misses++
+		entry = yearEntry{holidays: compute(year), computed: time.Now()}
+		c.years[year] = entry
+	}
+	return entry
+}
+
+type caldavEntry struct {
+	Name    string    `json:"name,omitempty"`
+	Holiday bool      `json:"holiday"`
+	Fetched time.Time `json:"fetched"`
+	// UID and Summary are those of the matching event, missing from entries persisted by previous versions
+	UID     string `json:"uid,omitempty"`
+	Summary string `json:"summary,omitempty"`
+}
+
+// caldavCacheFile is the on-disk representation of a CaldavCache
+type caldavCacheFile struct {
+	Updated time.Time              `json:"updated"`
+	Days    map[string]caldavEntry `json:"days"`
+}
+
+const dayKeyLayout = "2006-01-02"
+
+// CaldavCache keeps CalDAV holiday status per day. It can be shared between calendars reading the same CalDAV
+// calendar and persisted to disk to survive restarts and CalDAV outages.
+type CaldavCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	path    string
+	maxAge  time.Duration
+	entries map[string]caldavEntry
+	hits    uint64
+	misses  uint64
+	// dirty is set when entries changed since the last Flush
+	dirty bool
+	// flushMu serializes the writes of the cache file, outside of mu so that requests aren't blocked by disk I/O
+	flushMu sync.Mutex
+}
+
+// NewCaldavCache creates a cache keeping CalDAV holiday status of each day for ttl duration, disabled if ttl is zero
+func NewCaldavCache(ttl time.Duration) *CaldavCache {
+	return &CaldavCache{ttl: ttl, entries: make(map[string]caldavEntry)}
+}
+
+// Persist reads cached entries not older than maxAge from path and writes the cache to path on Flush. Entries
+// older than maxAge are ignored, but more recent ones are used when CalDAV is unavailable even if their ttl expired.
+// A missing file isn't an error.
+func (c *CaldavCache) Persist(path string, maxAge time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.path = path
+	c.maxAge = maxAge
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to read caldav cache file '%v': %w", path, err)
+	}
+	var f caldavCacheFile
+	if err := json.Unmarshal(content, &f); err != nil {
+		return fmt.Errorf("unable to decode caldav cache file '%v': %w", path, err)
+	}
+	for day, entry := range f.Days {
+		if time.Since(entry.Fetched) <= maxAge {
+			c.entries[day] = entry
+		}
+	}
+	return nil
+}
+
+func (c *CaldavCache) enabled() bool {
+	return c.ttl > 0 || c.path != ""
+}
+
+func (c *CaldavCache) get(day time.Time) (caldavEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ttl <= 0 {
+		return caldavEntry{}, false
+	}
+
+	entry, ok := c.entries[day.Format(dayKeyLayout)]
+	if !ok || time.Since(entry.Fetched) > c.ttl {
+		c.misses++
+		return caldavEntry{}, false
+	}
+	c.hits++
+	return entry, true
+}
+
+// stale returns the cached entry of day, even expired, if it's not older than the persisted max age
+func (c *CaldavCache) stale(day time.Time) (caldavEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.path == "" {
+		return caldavEntry{}, false
+	}
+
+	entry, ok := c.entries[day.Format(dayKeyLayout)]
+	if !ok || time.Since(entry.Fetched) > c.maxAge {
+		return caldavEntry{}, false
+	}
+	return entry, true
+}
+
+// set caches the entry of day, the cache file is written by the next Flush
+func (c *CaldavCache) set(day time.Time, entry caldavEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.enabled() {
+		return
+	}
+	c.entries[day.Format(dayKeyLayout)] = entry
+	c.dirty = true
+}
+
+// setDays caches the entry of each day, see set
+func (c *CaldavCache) setDays(entries map[time.Time]caldavEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.enabled() || len(entries) == 0 {
+		return
+	}
+	for day, entry := range entries {
+		c.entries[day.Format(dayKeyLayout)] = entry
+	}
+	c.dirty = true
+}
+
+// Clear removes all cached entries, such as when the summary patterns change, the cache file is written by the next
+// Flush
+func (c *CaldavCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]caldavEntry)
+	c.dirty = true
+}
+
+// Flush writes the cache to the file of Persist if it changed since the last Flush, through a temporary file renamed
+// to not corrupt the cache on failure. The file is written from a snapshot, without blocking cache reads and updates.
+func (c *CaldavCache) Flush() error {
+	c.flushMu.Lock()
+	defer c.flushMu.Unlock()
+
+	c.mu.Lock()
+	if c.path == "" || !c.dirty {
+		c.mu.Unlock()
+		return nil
+	}
+	path := c.path
+	content, err := json.Marshal(caldavCacheFile{Updated: time.Now(), Days: c.entries})
+	c.dirty = false
+	c.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("unable to encode caldav cache: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err = os.WriteFile(tmp, content, 0600); err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		// written again by the next Flush
+		c.mu.Lock()
+		c.dirty = true
+		c.mu.Unlock()
+		return fmt.Errorf("unable to write caldav cache file '%v': %w", path, err)
+	}
+	return nil
+}
+
+// WithCaldavCacheTTL keeps CalDAV holiday status of each day for ttl duration. Disabled when ttl is zero, the default.
+func WithCaldavCacheTTL(ttl time.Duration) Option {
+	return func(calendar *Calendar) {
+		calendar.caldavCache = NewCaldavCache(ttl)
+	}
+}
+
+// WithCaldavCache uses cache to keep CalDAV holiday status
+func WithCaldavCache(cache *CaldavCache) Option {
+	return func(calendar *Calendar) {
+		calendar.caldavCache = cache
+	}
+}
+
+// CacheStats returns the current state of the holidays and CalDAV caches
+func (cal *Calendar) CacheStats() CacheStats {
+	stats := CacheStats{Years: []int{}}
+
+	cal.holidayCache.mu.Lock()
+	for year := range cal.holidayCache.years {
+		stats.Years = append(stats.Years, year)
+	}
+	stats.HolidayHits = cal.holidayCache.hits
+	stats.HolidayMisses = cal.holidayCache.misses
+	cal.holidayCache.mu.Unlock()
+	sort.Ints(stats.Years)
+
+	cal.caldavCache.mu.Lock()
+	stats.CaldavSize = len(cal.caldavCache.entries)
+	stats.CaldavHits = cal.caldavCache.hits
+	stats.CaldavMisses = cal.caldavCache.misses
+	cal.caldavCache.mu.Unlock()
+
+	return stats
+}
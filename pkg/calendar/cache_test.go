@@ -0,0 +1,264 @@
+package calendar
+
+import (
+	"fmt"
+	"github.com/dolanor/caldav-go/caldav/entities"
+	"github.com/dolanor/caldav-go/icalendar/components"
+	"github.com/dolanor/caldav-go/icalendar/values"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type CountingCaldav struct {
+	MockCaldav
+	queries int
+}
+
+func (m *CountingCaldav) QueryEvents(path string, query *entities.CalendarQuery) ([]*components.Event, error) {
+	m.queries++
+	return m.MockCaldav.QueryEvents(path, query)
+}
+
+func TestCalendar_CacheStats(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	cdav := &CountingCaldav{
+		MockCaldav: MockCaldav{
+			events: []*components.Event{
+				{
+					UID:       "1",
+					DateStart: values.NewDateTime(time.Date(2022, time.April, 16, 0, 0, 0, 0, loc)),
+					DateEnd:   values.NewDateTime(time.Date(2022, time.April, 17, 0, 0, 0, 0, loc)),
+					Summary:   "Holidays",
+				},
+			},
+		},
+	}
+	c := New(loc,
+		WithCaldav(cdav),
+		WithCaldavSummaryPattern("Holidays"),
+		WithCaldavCacheTTL(time.Hour),
+	)
+
+	day := time.Date(2022, time.April, 16, 0, 0, 0, 0, loc)
+	for i := 0; i < 3; i++ {
+		if !c.IsHoliday(day) {
+			t.Errorf("%v should be a holiday", day)
+		}
+	}
+	c.GetHolidaysNamed(2023)
+
+	if cdav.queries != 1 {
+		t.Errorf("caldav should be queried once, got %d queries", cdav.queries)
+	}
+	want := CacheStats{
+		Years:         []int{2022, 2023},
+		HolidayHits:   2,
+		HolidayMisses: 2,
+		CaldavSize:    1,
+		CaldavHits:    2,
+		CaldavMisses:  1,
+	}
+	if got := c.CacheStats(); !reflect.DeepEqual(got, want) {
+		t.Errorf("CacheStats() got = %+v, want %+v", got, want)
+	}
+}
+
+func TestCalendar_CacheDisabled(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	cdav := &CountingCaldav{}
+	c := New(loc, WithCaldav(cdav), WithCaldavSummaryPattern("Holidays"))
+
+	day := time.Date(2022, time.April, 16, 0, 0, 0, 0, loc)
+	c.IsHoliday(day)
+	c.IsHoliday(day)
+	if cdav.queries != 2 {
+		t.Errorf("caldav should be queried on each call without cache, got %d queries", cdav.queries)
+	}
+	if size := c.CacheStats().CaldavSize; size != 0 {
+		t.Errorf("caldav cache should be empty, got %d entries", size)
+	}
+}
+
+type FailingCaldav struct{}
+
+func (m *FailingCaldav) QueryEvents(_ string, _ *entities.CalendarQuery) ([]*components.Event, error) {
+	return nil, fmt.Errorf("caldav unavailable")
+}
+
+func TestCaldavCache_Persist(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	path := filepath.Join(t.TempDir(), "cache.json")
+	day := time.Date(2022, time.April, 16, 0, 0, 0, 0, loc)
+
+	cache := NewCaldavCache(0)
+	if err := cache.Persist(path, time.Hour); err != nil {
+		t.Fatalf("unable to init cache from missing file: %v", err)
+	}
+	c := New(loc,
+		WithCaldav(&MockCaldav{
+			events: []*components.Event{
+				{
+					UID:       "1",
+					DateStart: values.NewDateTime(time.Date(2022, time.April, 16, 0, 0, 0, 0, loc)),
+					DateEnd:   values.NewDateTime(time.Date(2022, time.April, 17, 0, 0, 0, 0, loc)),
+					Summary:   "Holidays",
+				},
+			},
+		}),
+		WithCaldavSummaryPattern("Holidays"),
+		WithCaldavCache(cache),
+	)
+	if !c.IsHoliday(day) {
+		t.Errorf("%v should be a holiday", day)
+	}
+	if err := cache.Flush(); err != nil {
+		t.Fatalf("unable to write cache file: %v", err)
+	}
+
+	// Restart with an unavailable caldav server
+	restored := NewCaldavCache(0)
+	if err := restored.Persist(path, time.Hour); err != nil {
+		t.Fatalf("unable to read cache file: %v", err)
+	}
+	c = New(loc, WithCaldav(&FailingCaldav{}), WithCaldavSummaryPattern("Holidays"), WithCaldavCache(restored))
+	name, holiday, err := c.GetHolidayNameFromCaldav(day)
+	if err != nil {
+		t.Errorf("cached status should be used on caldav failure: %v", err)
+	}
+	if !holiday || name != "Holidays" {
+		t.Errorf("GetHolidayNameFromCaldav() got = (%v, %v), want (Holidays, true)", name, holiday)
+	}
+
+	// Entries older than max age are ignored
+	expired := NewCaldavCache(0)
+	if err := expired.Persist(path, 0); err != nil {
+		t.Fatalf("unable to read cache file: %v", err)
+	}
+	c = New(loc, WithCaldav(&FailingCaldav{}), WithCaldavSummaryPattern("Holidays"), WithCaldavCache(expired))
+	if _, _, err := c.GetHolidayNameFromCaldav(day); err == nil {
+		t.Error("expired cached status should be ignored")
+	}
+}
+
+func TestCaldavCache_Clear(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	path := filepath.Join(t.TempDir(), "cache.json")
+	day := time.Date(2022, time.April, 16, 0, 0, 0, 0, loc)
+	cdav := &CountingCaldav{
+		MockCaldav: MockCaldav{
+			events: []*components.Event{
+				{
+					UID:       "1",
+					DateStart: values.NewDateTime(day),
+					DateEnd:   values.NewDateTime(day.AddDate(0, 0, 1)),
+					Summary:   "Holidays",
+				},
+			},
+		},
+	}
+	cache := NewCaldavCache(time.Hour)
+	if err := cache.Persist(path, time.Hour); err != nil {
+		t.Fatalf("unable to init cache from missing file: %v", err)
+	}
+	c := New(loc, WithCaldav(cdav), WithCaldavSummaryPattern("Holidays"), WithCaldavCache(cache))
+	if !c.IsHoliday(day) {
+		t.Errorf("%v should be a holiday", day)
+	}
+
+	// the summary pattern changes
+	cache.Clear()
+	c = New(loc, WithCaldav(cdav), WithCaldavSummaryPattern("Congés"), WithCaldavCache(cache))
+	if c.IsHoliday(day) {
+		t.Errorf("%v should not be a holiday with the new pattern", day)
+	}
+	if cdav.queries != 2 {
+		t.Errorf("caldav should be queried again after clear, expected:2 ; actual:%v", cdav.queries)
+	}
+	if err := cache.Flush(); err != nil {
+		t.Fatalf("unable to write cache file: %v", err)
+	}
+
+	restored := NewCaldavCache(time.Hour)
+	if err := restored.Persist(path, time.Hour); err != nil {
+		t.Fatalf("unable to read cache file: %v", err)
+	}
+	if entry, ok := restored.get(day); !ok || entry.Holiday {
+		t.Errorf("cache file should be written on clear, actual:%+v", entry)
+	}
+}
+
+func TestCaldavCache_Flush(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	path := filepath.Join(t.TempDir(), "cache.json")
+	day := time.Date(2022, time.April, 16, 0, 0, 0, 0, loc)
+
+	cache := NewCaldavCache(time.Hour)
+	if err := cache.Persist(path, time.Hour); err != nil {
+		t.Fatalf("unable to init cache from missing file: %v", err)
+	}
+	if err := cache.Flush(); err != nil {
+		t.Fatalf("unable to flush cache: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("unchanged cache should not be written, stat error:%v", err)
+	}
+
+	cache.set(day, caldavEntry{Holiday: true, Name: "Holidays", Fetched: time.Now()})
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("cache file should not be written before flush, stat error:%v", err)
+	}
+	if err := cache.Flush(); err != nil {
+		t.Fatalf("unable to flush cache: %v", err)
+	}
+	restored := NewCaldavCache(time.Hour)
+	if err := restored.Persist(path, time.Hour); err != nil {
+		t.Fatalf("unable to read cache file: %v", err)
+	}
+	if entry, ok := restored.get(day); !ok || !entry.Holiday {
+		t.Errorf("cache file should be written on flush, actual:%+v", entry)
+	}
+
+	// a failed write is retried by the next flush
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("unable to remove cache file: %v", err)
+	}
+	if err := os.Mkdir(path+".tmp", 0o700); err != nil {
+		t.Fatalf("unable to create directory: %v", err)
+	}
+	cache.set(day.AddDate(0, 0, 1), caldavEntry{Fetched: time.Now()})
+	if err := cache.Flush(); err == nil {
+		t.Errorf("flush should fail when the temporary file can't be written")
+	}
+	if err := os.Remove(path + ".tmp"); err != nil {
+		t.Fatalf("unable to remove directory: %v", err)
+	}
+	if err := cache.Flush(); err != nil {
+		t.Fatalf("unable to flush cache: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("cache file should be written on the flush following a failure: %v", err)
+	}
+}
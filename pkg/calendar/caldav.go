@@ -0,0 +1,138 @@
+package calendar
+
+import (
+	"fmt"
+	"github.com/avast/retry-go"
+	"github.com/dolanor/caldav-go/caldav"
+	"github.com/dolanor/caldav-go/caldav/entities"
+	"github.com/dolanor/caldav-go/icalendar/components"
+	"net/http"
+	"time"
+)
+
+type Caldav interface {
+	QueryEvents(path string, query *entities.CalendarQuery) (events []*components.Event, oerr error)
+}
+
+type caldavConfig struct {
+	client        *http.Client
+	attempts      uint
+	logger        Logger
+	rateLimitHook func(until time.Time)
+	tunings       []func(transport *http.Transport)
+}
+
+type CaldavOption func(config *caldavConfig)
+
+// WithHTTPClient uses client for CalDAV requests instead of http.DefaultClient, to configure TLS client certificates,
+// proxies or timeouts. Note that the client timeout bounds each CalDAV request, whatever the remaining time of the
+// calendar request that triggered the query.
+func WithHTTPClient(client *http.Client) CaldavOption {
+	return func(config *caldavConfig) {
+		config.client = client
+	}
+}
+
+// WithValidateAttempts sets how many times the CalDAV connection is tried before failing, 1000 by default
+func WithValidateAttempts(attempts uint) CaldavOption {
+	return func(config *caldavConfig) {
+		config.attempts = attempts
+	}
+}
+
+// WithCaldavLogger logs failed connection attempts with logger, discarded by default
+func WithCaldavLogger(logger Logger) CaldavOption {
+	return func(config *caldavConfig) {
+		config.logger = logger
+	}
+}
+
+// WithMaxIdleConns sets the maximum number of idle CalDAV connections kept open, 100 by default, 0 for no limit
+func WithMaxIdleConns(n int) CaldavOption {
+	return func(config *caldavConfig) {
+		config.tunings = append(config.tunings, func(transport *http.Transport) {
+			transport.MaxIdleConns = n
+		})
+	}
+}
+
+// WithIdleConnTimeout closes idle CalDAV connections after timeout, 90s by default, 0 for no timeout. Set it below the
+// idle timeout of the CalDAV server to avoid reusing connections it already closed.
+func WithIdleConnTimeout(timeout time.Duration) CaldavOption {
+	return func(config *caldavConfig) {
+		config.tunings = append(config.tunings, func(transport *http.Transport) {
+			transport.IdleConnTimeout = timeout
+		})
+	}
+}
+
+// WithDisableKeepAlives opens a new connection for each CalDAV request if disable is true, keep-alives are enabled by
+// default
+func WithDisableKeepAlives(disable bool) CaldavOption {
+	return func(config *caldavConfig) {
+		config.tunings = append(config.tunings, func(transport *http.Transport) {
+			transport.DisableKeepAlives = disable
+		})
+	}
+}
+
+// transport returns the transport of the client, tuned by the connection options on a copy. A custom transport that
+// isn't an *http.Transport can't be tuned and is returned as is.
+func (config *caldavConfig) transport() http.RoundTripper {
+	next := config.client.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if len(config.tunings) == 0 {
+		return next
+	}
+	t, ok := next.(*http.Transport)
+	if !ok {
+		config.logger.Warnf("unable to tune caldav connections of transport %T", next)
+		return next
+	}
+	t = t.Clone()
+	for _, tune := range config.tunings {
+		tune(t)
+	}
+	return t
+}
+
+func NewCaldav(caldavUrl, caldavPath string, opts ...CaldavOption) (Caldav, error) {
+	config := caldavConfig{client: http.DefaultClient, attempts: 1000, logger: noopLogger{}}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	// honor 429 responses whatever the injected client
+	httpClient := *config.client
+	transport := &rateLimitTransport{next: config.transport(), now: time.Now, hook: config.rateLimitHook, logger: config.logger}
+	httpClient.Transport = transport
+
+	// create a reference to your CalDAV-compliant server
+	server, _ := caldav.NewServer(caldavUrl)
+	// create a CalDAV client to speak to the server
+	var client = caldav.NewClient(server, &httpClient)
+	err := retry.Do(
+		func() error {
+			// start executing requests!
+			err := client.ValidateServer(caldavPath)
+			if err != nil {
+				return fmt.Errorf("bad caldav configuration, unable to validate connexion: %w", err)
+			}
+			return nil
+		},
+		retry.OnRetry(
+			func(n uint, err error) {
+				config.logger.Errorf("unable to validate caldav connection on retry %d: %v", n, err)
+			},
+		),
+		retry.Attempts(config.attempts),
+		retry.DelayType(retry.BackOffDelay),
+		retry.MaxDelay(24*time.Hour),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to validate caldav connection: %w", err)
+	}
+	return &rateLimitedCaldav{Client: client, transport: transport}, nil
+}
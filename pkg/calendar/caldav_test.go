@@ -0,0 +1,112 @@
+package calendar
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type countingTransport struct {
+	requests int
+}
+
+func (c *countingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	c.requests++
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestNewCaldav_WithHTTPClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodOptions {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("DAV", "1, 2, calendar-access")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	transport := &countingTransport{}
+	cdav, err := NewCaldav(server.URL, "/calendars/", WithHTTPClient(&http.Client{Transport: transport}))
+	if err != nil {
+		t.Fatalf("unable to init caldav: %v", err)
+	}
+	if cdav == nil {
+		t.Error("caldav client should be returned")
+	}
+	if transport.requests == 0 {
+		t.Error("injected http client should be used to validate caldav connection")
+	}
+}
+
+func TestCaldavConfig_Transport(t *testing.T) {
+	custom := &countingTransport{}
+	tests := []struct {
+		name              string
+		client            *http.Client
+		opts              []CaldavOption
+		wantMaxIdleConns  int
+		wantIdleTimeout   time.Duration
+		wantNoKeepAlives  bool
+		wantUntouchedNext http.RoundTripper
+	}{
+		{
+			name:              "Default transport",
+			client:            http.DefaultClient,
+			wantUntouchedNext: http.DefaultTransport,
+		},
+		{
+			name:             "Tuned default transport",
+			client:           http.DefaultClient,
+			opts:             []CaldavOption{WithMaxIdleConns(2), WithIdleConnTimeout(5 * time.Second), WithDisableKeepAlives(true)},
+			wantMaxIdleConns: 2,
+			wantIdleTimeout:  5 * time.Second,
+			wantNoKeepAlives: true,
+		},
+		{
+			name:             "Tuned client transport",
+			client:           &http.Client{Transport: &http.Transport{MaxIdleConns: 10}},
+			opts:             []CaldavOption{WithIdleConnTimeout(time.Second)},
+			wantMaxIdleConns: 10,
+			wantIdleTimeout:  time.Second,
+		},
+		{
+			name:              "Custom transport",
+			client:            &http.Client{Transport: custom},
+			opts:              []CaldavOption{WithMaxIdleConns(2)},
+			wantUntouchedNext: custom,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := caldavConfig{client: tt.client, logger: noopLogger{}}
+			for _, opt := range tt.opts {
+				opt(&config)
+			}
+			got := config.transport()
+			if tt.wantUntouchedNext != nil {
+				if got != tt.wantUntouchedNext {
+					t.Errorf("transport should not be replaced, expected:%v ; actual:%v", tt.wantUntouchedNext, got)
+				}
+				return
+			}
+			transport, ok := got.(*http.Transport)
+			if !ok {
+				t.Fatalf("bad transport type: %T", got)
+			}
+			if transport == http.DefaultTransport || transport == tt.client.Transport {
+				t.Error("tuned transport should be a copy")
+			}
+			if transport.MaxIdleConns != tt.wantMaxIdleConns {
+				t.Errorf("bad MaxIdleConns, expected:%v ; actual:%v", tt.wantMaxIdleConns, transport.MaxIdleConns)
+			}
+			if transport.IdleConnTimeout != tt.wantIdleTimeout {
+				t.Errorf("bad IdleConnTimeout, expected:%v ; actual:%v", tt.wantIdleTimeout, transport.IdleConnTimeout)
+			}
+			if transport.DisableKeepAlives != tt.wantNoKeepAlives {
+				t.Errorf("bad DisableKeepAlives, expected:%v ; actual:%v", tt.wantNoKeepAlives, transport.DisableKeepAlives)
+			}
+		})
+	}
+}
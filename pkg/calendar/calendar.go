@@ -1,56 +1,51 @@
 package calendar
 
 import (
+	"errors"
 	"fmt"
 	"github.com/avast/retry-go"
-	"github.com/dolanor/caldav-go/caldav"
 	"github.com/dolanor/caldav-go/caldav/entities"
 	"github.com/dolanor/caldav-go/icalendar/components"
-	"go.uber.org/zap"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
 	"math"
-	"net/http"
+	"sort"
 	"strings"
 	"time"
+	"unicode"
 )
 
-type Caldav interface {
-	QueryEvents(path string, query *entities.CalendarQuery) (events []*components.Event, oerr error)
-}
-
 type Calendar struct {
-	Location             *time.Location
-	cdav                 Caldav
-	caldavPath           string
-	caldavSummaryPattern string
-}
-
-func NewCaldav(caldavUrl, caldavPath string) (Caldav, error) {
-	// create a reference to your CalDAV-compliant server
-	server, _ := caldav.NewServer(caldavUrl)
-	// create a CalDAV client to speak to the server
-	var client = caldav.NewClient(server, http.DefaultClient)
-	err := retry.Do(
-		func() error {
-			// start executing requests!
-			err := client.ValidateServer(caldavPath)
-			if err != nil {
-				return fmt.Errorf("bad caldav configuration, unable to validate connexion: %w", err)
-			}
-			return nil
-		},
-		retry.OnRetry(
-			func(n uint, err error) {
-				zap.S().Errorf("unable to validate caldav connection on retry %d: %v", n, err)
-			},
-		),
-		retry.Attempts(1000),
-		retry.DelayType(retry.BackOffDelay),
-		retry.MaxDelay(24*time.Hour),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("unable to validate caldav connection: %w", err)
-	}
-	return client, nil
+	Location              *time.Location
+	cdav                  Caldav
+	caldavPath            string
+	caldavSummaryPatterns []string
+	caldavNameExtractor   func(summary string) string
+	caldavWindowMargin    time.Duration
+	caldavAllDayOnly      bool
+	caldavMaxEvents       int
+	caldavCaseInsensitive bool
+	caldavNormalize       bool
+	caldavQueryAttempts   uint
+	nationalHolidays      bool
+	pentecostMonday       bool
+	region                Region
+	goodFriday            bool
+	saintStephen          bool
+	bridgeDays            bool
+	customHolidays        []CustomHoliday
+	excludedHolidays      map[string]bool
+	halfDays              map[string]bool
+	weekendObservance     bool
+	weekend               map[time.Weekday]bool
+	workingHours          *WorkingHours
+	caldavDays            *caldavDays
+	govAPI                *govHolidayAPI
+	holidayCache          *holidayCache
+	caldavCache           *CaldavCache
+	logger                Logger
+	clock                 func() time.Time
 }
 
 type Option func(calendar *Calendar)
@@ -61,9 +56,30 @@ func WithCaldav(cdav Caldav) Option {
 	}
 }
 
+// WithCaldavSummaryPattern sets the text that summaries of holiday CalDAV events contain. No CalDAV event is a holiday
+// while the pattern is empty, the default.
 func WithCaldavSummaryPattern(caldavSummaryPattern string) Option {
+	return WithCaldavSummaryPatterns([]string{caldavSummaryPattern})
+}
+
+// WithCaldavSummaryPatterns sets several texts, such as Congés, RTT and Télétravail, a CalDAV event being a holiday if
+// its summary contains any of them. It replaces the pattern of WithCaldavSummaryPattern. Empty patterns are ignored.
+func WithCaldavSummaryPatterns(patterns []string) Option {
 	return func(calendar *Calendar) {
-		calendar.caldavSummaryPattern = caldavSummaryPattern
+		calendar.caldavSummaryPatterns = nil
+		for _, p := range patterns {
+			if p != "" {
+				calendar.caldavSummaryPatterns = append(calendar.caldavSummaryPatterns, p)
+			}
+		}
+	}
+}
+
+// WithCaldavNameExtractor configures how the holiday name is extracted from the summary of a matching CalDAV event.
+// By default, the full summary is used.
+func WithCaldavNameExtractor(extractor func(summary string) string) Option {
+	return func(calendar *Calendar) {
+		calendar.caldavNameExtractor = extractor
 	}
 }
 
@@ -73,12 +89,132 @@ func WithCaldavPath(caldavPath string) Option {
 	}
 }
 
+// WithCaldavWindowMargin extends the CalDAV query window of a day by margin on both sides. The whole local day is
+// always queried, a margin helps with servers comparing all-day events in another timezone. Returned events are
+// still filtered on the local day.
+func WithCaldavWindowMargin(margin time.Duration) Option {
+	return func(calendar *Calendar) {
+		calendar.caldavWindowMargin = margin
+	}
+}
+
+// WithCaldavAllDayOnly ignores timed CalDAV events, such as a meeting whose summary matches the pattern, when enabled.
+// Only events covering whole days are holidays.
+func WithCaldavAllDayOnly(allDayOnly bool) Option {
+	return func(calendar *Calendar) {
+		calendar.caldavAllDayOnly = allDayOnly
+	}
+}
+
+// WithCaldavMaxEvents scans at most maxEvents events of a CalDAV query, a warning is logged when a query returns more
+// events, usually because the CalDAV path points to a busy shared calendar. No limit if 0.
+func WithCaldavMaxEvents(maxEvents int) Option {
+	return func(calendar *Calendar) {
+		calendar.caldavMaxEvents = maxEvents
+	}
+}
+
+// WithCaldavCaseInsensitive ignores case when matching CalDAV event summaries with the summary pattern, case is
+// significant by default
+func WithCaldavCaseInsensitive(caseInsensitive bool) Option {
+	return func(calendar *Calendar) {
+		calendar.caldavCaseInsensitive = caseInsensitive
+	}
+}
+
+// WithCaldavNormalize normalizes CalDAV event summaries and the summary pattern before matching them, if normalize is
+// true: surrounding spaces are trimmed and accents removed, so that "Congés  " matches "Conges". Summaries are matched
+// as is by default.
+func WithCaldavNormalize(normalize bool) Option {
+	return func(calendar *Calendar) {
+		calendar.caldavNormalize = normalize
+	}
+}
+
+// Delays between attempts of a CalDAV query, doubled on each retry, see WithCaldavQueryAttempts
+const (
+	caldavQueryRetryDelay    = 100 * time.Millisecond
+	caldavQueryMaxRetryDelay = time.Second
+)
+
+// WithCaldavQueryAttempts sets how many times each CalDAV query is tried before its error is returned, with a short
+// backoff, to smooth over transient network errors. 1 by default, no retry. Rate limited queries are not retried.
+// Unlike WithValidateAttempts, it applies to the queries of each request.
+func WithCaldavQueryAttempts(attempts uint) Option {
+	return func(calendar *Calendar) {
+		if attempts < 1 {
+			attempts = 1
+		}
+		calendar.caldavQueryAttempts = attempts
+	}
+}
+
+// WithClock reads the current time from clock instead of time.Now, to pin the current day of IsWorkingDayToday and
+// IsWorkingHoursNow. Cache expiration still relies on the real time.
+func WithClock(clock func() time.Time) Option {
+	return func(calendar *Calendar) {
+		calendar.clock = clock
+	}
+}
+
+// Now returns the current time of the calendar clock, see WithClock
+func (cal *Calendar) Now() time.Time {
+	return cal.clock()
+}
+
+// WithNationalHolidays configures whether national holidays are holidays, enabled by default. When disabled, only
+// CalDAV holidays and custom ones, see WithCustomHolidays, are holidays: region and other national holiday options have
+// no effect.
+func WithNationalHolidays(enabled bool) Option {
+	return func(calendar *Calendar) {
+		calendar.nationalHolidays = enabled
+	}
+}
+
+// WithPentecostMonday configures whether Lundi de Pentecôte is a holiday. As "journée de solidarité", it is worked
+// by some employers. Enabled by default.
+func WithPentecostMonday(holiday bool) Option {
+	return func(calendar *Calendar) {
+		calendar.pentecostMonday = holiday
+	}
+}
+
+// WithGoodFriday adds Vendredi saint, already a holiday in RegionAlsaceMoselle
+func WithGoodFriday() Option {
+	return func(calendar *Calendar) {
+		calendar.goodFriday = true
+	}
+}
+
+// WithSaintStephen adds Saint Étienne on 26 December, already a holiday in RegionAlsaceMoselle
+func WithSaintStephen() Option {
+	return func(calendar *Calendar) {
+		calendar.saintStephen = true
+	}
+}
+
+// WithBridgeDays reports bridge days ("ponts") as non-working days, see IsBridgeDay
+func WithBridgeDays(bridgeDays bool) Option {
+	return func(calendar *Calendar) {
+		calendar.bridgeDays = bridgeDays
+	}
+}
+
 func New(location *time.Location, opts ...Option) *Calendar {
 	c := &Calendar{
-		location,
-		nil,
-		"",
-		"",
+		Location: location,
+		caldavNameExtractor: func(summary string) string {
+			return summary
+		},
+		caldavQueryAttempts: 1,
+		nationalHolidays:    true,
+		pentecostMonday:     true,
+		region:              RegionMetropole,
+		holidayCache:        newHolidayCache(),
+		caldavCache:         NewCaldavCache(0),
+		logger:              noopLogger{},
+		weekend:             defaultWeekend,
+		clock:               time.Now,
 	}
 
 	for _, opt := range opts {
@@ -87,7 +223,34 @@ func New(location *time.Location, opts ...Option) *Calendar {
 	return c
 }
 
+// Years of the Gregorian calendar supported by the computations, earlier years predate its adoption in 1582
+const (
+	MinYear = 1583
+	MaxYear = 9999
+)
+
+// ValidYear returns true if year is within [MinYear, MaxYear]
+func ValidYear(year int) bool {
+	return year >= MinYear && year <= MaxYear
+}
+
+// academicYearStart is the first month of French school years, running from September to August
+const academicYearStart = time.September
+
+// AcademicYear returns the year the school year of t started, such as 2023 for July 2024 and 2024 for September 2024,
+// in the location of t
+func AcademicYear(t time.Time) int {
+	if t.Month() < academicYearStart {
+		return t.Year() - 1
+	}
+	return t.Year()
+}
+
+// GetEasterDay returns the Easter day of the year, or a zero time if the year isn't valid, see ValidYear
 func (cal *Calendar) GetEasterDay(year int) time.Time {
+	if !ValidYear(year) {
+		return time.Time{}
+	}
 	g := float64(year % 19.0)
 	c := math.Floor(float64(year) / 100.0)
 	c4 := math.Floor(c / 4.0)
@@ -116,37 +279,154 @@ func (cal *Calendar) GetEasterDay(year int) time.Time {
 		day = presJour
 	}
 
-	return time.Date(year, 3, 31, 0, 0, 0, 0, cal.Location).AddDate(0, 0, day)
+	// AddDate may land on a DST transition, normalize to local midnight
+	return cal.midnight(time.Date(year, 3, 31, 0, 0, 0, 0, cal.Location).AddDate(0, 0, day))
 }
 
-func (cal *Calendar) GetHolidays(year int) *[]time.Time {
+// GetOrthodoxEasterDay returns the Orthodox Easter day, computed in the Julian calendar with the Meeus algorithm and
+// converted to the Gregorian calendar, or a zero time if the year isn't valid, see ValidYear
+func (cal *Calendar) GetOrthodoxEasterDay(year int) time.Time {
+	if !ValidYear(year) {
+		return time.Time{}
+	}
+	a := year % 4
+	b := year % 7
+	c := year % 19
+	d := (19*c + 15) % 30
+	e := (2*a + 4*b - d + 34) % 7
+	month := (d + e + 114) / 31
+	day := (d+e+114)%31 + 1
+
+	// Écart entre les calendriers julien et grégorien, 13 jours de 1900 à 2099
+	julianOffset := year/100 - year/400 - 2
+	return time.Date(year, time.Month(month), day+julianOffset, 0, 0, 0, 0, cal.Location)
+}
+
+// midnight returns the start of the day of date in the calendar location
+func (cal *Calendar) midnight(date time.Time) time.Time {
+	d := date.In(cal.Location)
+	return time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, cal.Location)
+}
+
+// MardiGras returns Shrove Tuesday, 47 days before Easter
+func (cal *Calendar) MardiGras(year int) time.Time {
+	return cal.GetEasterDay(year).AddDate(0, 0, -47)
+}
+
+// AshWednesday returns the first day of Lent, 46 days before Easter
+func (cal *Calendar) AshWednesday(year int) time.Time {
+	return cal.GetEasterDay(year).AddDate(0, 0, -46)
+}
+
+// AscensionDay returns the Ascension Thursday, 39 days after Easter
+func (cal *Calendar) AscensionDay(year int) time.Time {
+	return cal.GetEasterDay(year).AddDate(0, 0, 39)
+}
+
+// PentecostSunday returns the Pentecost, 49 days after Easter
+func (cal *Calendar) PentecostSunday(year int) time.Time {
+	return cal.GetEasterDay(year).AddDate(0, 0, 49)
+}
+
+// Holiday is a named day off. Key identifies national holidays whatever the language of Name, see Localized. Observed
+// is set on the weekday observed for a weekend holiday, see WithWeekendObservance.
+type Holiday struct {
+	Date     time.Time `json:"date"`
+	Name     string    `json:"name"`
+	Key      string    `json:"key,omitempty"`
+	Source   string    `json:"source,omitempty"`
+	Observed bool      `json:"observed,omitempty"`
+}
+
+// Sources of holidays
+const (
+	SourceNational = "national"
+	SourceCustom   = "custom"
+	SourceCaldav   = "caldav"
+)
+
+// GetHolidaysNamed returns the holidays of the year, none if the year isn't valid, see ValidYear
+func (cal *Calendar) GetHolidaysNamed(year int) []Holiday {
+	if !ValidYear(year) {
+		return []Holiday{}
+	}
+	return cal.withObserved(year, cal.baseHolidays(year))
+}
+
+// HolidaysModTime returns when the holidays of the year returned by GetHolidaysNamed were computed
+func (cal *Calendar) HolidaysModTime(year int) time.Time {
+	return cal.holidayCache.entry(year, cal.computeHolidays).computed
+}
+
+// computeHolidays returns the holidays of the year, a single one per day. On the same day, the most specific source
+// wins: custom holidays, of the holidays file, over region holidays over national ones.
+func (cal *Calendar) computeHolidays(year int) []Holiday {
+	if !ValidYear(year) {
+		return nil
+	}
+	if !cal.nationalHolidays {
+		return uniqueHolidays(cal.customHolidaysOf(year))
+	}
+	return uniqueHolidays(cal.customHolidaysOf(year), cal.regionHolidays(year), cal.nationalHolidaysOf(year))
+}
+
+// uniqueHolidays merges sources, by decreasing precedence, keeping the first holiday of each day, sorted by date
+func uniqueHolidays(sources ...[]Holiday) []Holiday {
+	var holidays []Holiday
+	seen := make(map[time.Time]bool)
+	for _, source := range sources {
+		for _, h := range source {
+			if !seen[h.Date] {
+				seen[h.Date] = true
+				holidays = append(holidays, h)
+			}
+		}
+	}
+	sort.Slice(holidays, func(i, j int) bool {
+		return holidays[i].Date.Before(holidays[j].Date)
+	})
+	return holidays
+}
+
+// nationalHolidaysOf returns the national holidays of the year, with the optional ones enabled
+func (cal *Calendar) nationalHolidaysOf(year int) []Holiday {
 
 	// Calcul du jour de pâques
 	paques := cal.GetEasterDay(year)
 
-	joursFeries := []time.Time{
-		// Jour de l'an
-		time.Date(year, time.January, 1, 0, 0, 0, 0, cal.Location),
-		// Easter
-		paques.AddDate(0, 0, 1),
-		// 1 mai
-		time.Date(year, time.May, 1, 0, 0, 0, 0, cal.Location),
-		// 8 mai
-		time.Date(year, time.May, 8, 0, 0, 0, 0, cal.Location),
-		// Ascension
-		paques.AddDate(0, 0, 39),
-		// 14 juillet
-		time.Date(year, time.July, 14, 0, 0, 0, 0, cal.Location),
-		// 15 aout
-		time.Date(year, time.August, 15, 0, 0, 0, 0, cal.Location),
-		// Toussaint
-		time.Date(year, time.November, 1, 0, 0, 0, 0, cal.Location),
-		// 11 novembre
-		time.Date(year, time.November, 11, 0, 0, 0, 0, cal.Location),
-		// noël
-		time.Date(year, time.December, 25, 0, 0, 0, 0, cal.Location),
+	joursFeries := []Holiday{
+		nationalHoliday(time.Date(year, time.January, 1, 0, 0, 0, 0, cal.Location), "new_year"),
+		nationalHoliday(paques.AddDate(0, 0, 1), "easter_monday"),
+		nationalHoliday(time.Date(year, time.May, 1, 0, 0, 0, 0, cal.Location), "labour_day"),
+		nationalHoliday(time.Date(year, time.May, 8, 0, 0, 0, 0, cal.Location), "victory_day"),
+		nationalHoliday(cal.AscensionDay(year), "ascension"),
+	}
+	if cal.pentecostMonday {
+		joursFeries = append(joursFeries, nationalHoliday(cal.PentecostSunday(year).AddDate(0, 0, 1), "pentecost_monday"))
+	}
+	joursFeries = append(joursFeries,
+		nationalHoliday(time.Date(year, time.July, 14, 0, 0, 0, 0, cal.Location), "national_day"),
+		nationalHoliday(time.Date(year, time.August, 15, 0, 0, 0, 0, cal.Location), "assumption"),
+		nationalHoliday(time.Date(year, time.November, 1, 0, 0, 0, 0, cal.Location), "all_saints"),
+		nationalHoliday(time.Date(year, time.November, 11, 0, 0, 0, 0, cal.Location), "armistice"),
+		nationalHoliday(time.Date(year, time.December, 25, 0, 0, 0, 0, cal.Location), "christmas"),
+	)
+
+	if cal.goodFriday {
+		joursFeries = append(joursFeries, nationalHoliday(paques.AddDate(0, 0, -2), "good_friday"))
+	}
+	if cal.saintStephen {
+		joursFeries = append(joursFeries, nationalHoliday(time.Date(year, time.December, 26, 0, 0, 0, 0, cal.Location), "saint_stephen"))
 	}
+	return joursFeries
+}
 
+func (cal *Calendar) GetHolidays(year int) *[]time.Time {
+	holidays := cal.GetHolidaysNamed(year)
+	joursFeries := make([]time.Time, 0, len(holidays))
+	for _, h := range holidays {
+		joursFeries = append(joursFeries, h.Date)
+	}
 	return &joursFeries
 }
 
@@ -159,46 +439,753 @@ func (cal *Calendar) GetHolidaysSet(year int) map[time.Time]bool {
 	return result
 }
 
+// IsHoliday returns true if date is a national holiday or a CalDAV one, see HolidayAt to know which source matched
 func (cal *Calendar) IsHoliday(date time.Time) bool {
-	h := cal.GetHolidaysSet(date.Year())
-	d := date.In(cal.Location)
-	day := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, cal.Location)
-	caldavHolidays, err := cal.IsHolidaysFromCaldav(day)
+	day := cal.midnight(date)
+	if cal.isHolidayDay(day) {
+		return true
+	}
+	_, holiday, err := cal.GetHolidayNameFromCaldav(day)
+	if err != nil {
+		cal.logger.Errorf("unable to check holidays from caldav: %v", err)
+	}
+	return holiday
+}
+
+// isHolidayDay returns true if day, at midnight, is one of the holidays returned by GetHolidaysNamed. Holiday days
+// are cached sorted per year and searched without allocation, unless official holidays are fetched as they may
+// change, or observed holidays are added as they depend on the holidays of the adjacent years.
+func (cal *Calendar) isHolidayDay(day time.Time) bool {
+	var days []time.Time
+	if cal.govAPI != nil || cal.weekendObservance {
+		days = holidayDays(cal.GetHolidaysNamed(day.Year()))
+	} else {
+		days = cal.holidayCache.days(day.Year(), cal.computeHolidays, func(holidays []Holiday) []time.Time {
+			return holidayDays(cal.withoutExcluded(holidays))
+		})
+	}
+	i := sort.Search(len(days), func(i int) bool {
+		return !days[i].Before(day)
+	})
+	return i < len(days) && days[i].Equal(day)
+}
+
+// holidayDays returns the sorted days of holidays
+func holidayDays(holidays []Holiday) []time.Time {
+	days := make([]time.Time, 0, len(holidays))
+	for _, h := range holidays {
+		days = append(days, h.Date)
+	}
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Before(days[j])
+	})
+	return days
+}
+
+// HolidayName returns the name of the holiday at date, national holidays first then CalDAV ones
+func (cal *Calendar) HolidayName(date time.Time) (string, bool) {
+	h, holiday := cal.HolidayAt(date)
+	return h.Name, holiday
+}
+
+// HolidayAt returns the holiday at date, national holidays first then CalDAV ones
+func (cal *Calendar) HolidayAt(date time.Time) (Holiday, bool) {
+	day := cal.midnight(date)
+	for _, h := range cal.GetHolidaysNamed(day.Year()) {
+		if h.Date.Equal(day) {
+			return h, true
+		}
+	}
+	name, holiday, err := cal.GetHolidayNameFromCaldav(day)
+	if err != nil {
+		cal.logger.Errorf("unable to check holidays from caldav: %v", err)
+	}
+	if !holiday {
+		return Holiday{}, false
+	}
+	return Holiday{Date: day, Name: name, Source: SourceCaldav}, true
+}
+
+// holidaySearchHorizon bounds the search of the next or previous holiday
+const holidaySearchHorizon = 366
+
+// NextHoliday returns the first holiday, national or from CalDAV, at or after from. A zero time is returned if no
+// holiday is found within a year.
+func (cal *Calendar) NextHoliday(from time.Time) (time.Time, string) {
+	day := cal.midnight(from)
+	horizon := day.AddDate(0, 0, holidaySearchHorizon)
+
+	next := Holiday{Date: horizon}
+	for year := day.Year(); year <= horizon.Year(); year++ {
+		for _, h := range cal.GetHolidaysNamed(year) {
+			if !h.Date.Before(day) && h.Date.Before(next.Date) {
+				next = h
+			}
+		}
+	}
+
+	caldavHolidays, err := cal.caldavHolidays(day, next.Date)
+	if err != nil {
+		cal.logger.Errorf("unable to check holidays from caldav: %v", err)
+	}
+	if len(caldavHolidays) > 0 && caldavHolidays[0].Date.Before(next.Date) {
+		next = caldavHolidays[0]
+	}
+
+	if !next.Date.Before(horizon) {
+		return time.Time{}, ""
+	}
+	return next.Date, next.Name
+}
+
+// MaxUpcomingHolidays is the maximum number of holidays returned by UpcomingHolidays
+const MaxUpcomingHolidays = 50
+
+// upcomingHolidaysYears bounds the number of years read by UpcomingHolidays, for calendars with few holidays
+const upcomingHolidaysYears = 10
+
+// UpcomingHolidays returns the next n holidays, national and from CalDAV, at or after from, sorted by date. n is capped
+// to MaxUpcomingHolidays, and less holidays are returned if they aren't found within upcomingHolidaysYears years.
+// CalDAV is queried once per year read, its errors are logged and only national holidays are returned.
+func (cal *Calendar) UpcomingHolidays(from time.Time, n int) []Holiday {
+	if n < 0 {
+		n = 0
+	}
+	if n > MaxUpcomingHolidays {
+		n = MaxUpcomingHolidays
+	}
+	holidays := make([]Holiday, 0, n)
+	start := cal.midnight(from)
+	for i := 0; i < upcomingHolidaysYears && len(holidays) < n; i++ {
+		end := time.Date(start.Year(), time.December, 31, 0, 0, 0, 0, cal.Location)
+		holidays = append(holidays, cal.holidaysBetween(start, end)...)
+		start = end.AddDate(0, 0, 1)
+	}
+	if len(holidays) > n {
+		holidays = holidays[:n]
+	}
+	return holidays
+}
+
+// IsNationalHoliday returns true if date is a national or custom holiday, CalDAV isn't queried
+func (cal *Calendar) IsNationalHoliday(date time.Time) bool {
+	return cal.isHolidayDay(cal.midnight(date))
+}
+
+// IsWorkingDayNational is IsWorkingDay ignoring CalDAV holidays, it doesn't query CalDAV
+func (cal *Calendar) IsWorkingDayNational(date time.Time) bool {
+	day := cal.midnight(date)
+	if cal.bridgeDays {
+		if adjacent, ok := cal.bridgeAdjacent(day); ok && !cal.IsNationalHoliday(day) && cal.IsNationalHoliday(adjacent) {
+			return false
+		}
+	}
+	return !cal.IsNationalHoliday(day) && cal.IsWeekDay(day)
+}
+
+// HolidayCount returns the number of holidays in the year, national and from CalDAV. A day both a national and a
+// CalDAV holiday is counted once.
+func (cal *Calendar) HolidayCount(year int) int {
+	return len(cal.HolidaysOfYear(year))
+}
+
+// YearStats are the day counts of a year, see Calendar.YearStats
+type YearStats struct {
+	TotalDays   int
+	WeekendDays int
+	Holidays    int
+	WorkingDays int
+	BridgeDays  int
+}
+
+// YearStats returns the day counts of the year: its days, weekend days, holidays, national and from CalDAV whatever
+// the day of the week, working days and bridge days, see IsBridgeDay. CalDAV is queried once for the whole year.
+func (cal *Calendar) YearStats(year int) YearStats {
+	first := time.Date(year, time.January, 1, 0, 0, 0, 0, cal.Location)
+	last := time.Date(year, time.December, 31, 0, 0, 0, 0, cal.Location)
+	c := cal.ForRange(first, last)
+	stats := YearStats{Holidays: c.HolidayCount(year)}
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		stats.TotalDays++
+		if c.isWeekend(day) {
+			stats.WeekendDays++
+		}
+		if c.IsWorkingDay(day) {
+			stats.WorkingDays++
+		}
+		if c.IsBridgeDay(day) {
+			stats.BridgeDays++
+		}
+	}
+	return stats
+}
+
+// HolidaysOfYear returns the holidays of the year, national and from CalDAV, sorted by date. A day both a national and
+// a CalDAV holiday is returned once, as the national holiday. CalDAV errors are logged and only national holidays
+// are returned.
+func (cal *Calendar) HolidaysOfYear(year int) []Holiday {
+	return cal.holidaysBetween(
+		time.Date(year, time.January, 1, 0, 0, 0, 0, cal.Location),
+		time.Date(year, time.December, 31, 0, 0, 0, 0, cal.Location),
+	)
+}
+
+// HolidaysOfMonth returns the holidays of the month, national and from CalDAV, sorted by date, see HolidaysOfYear
+func (cal *Calendar) HolidaysOfMonth(year int, month time.Month) []Holiday {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, cal.Location)
+	return cal.holidaysBetween(first, first.AddDate(0, 1, -1))
+}
+
+// holidaysBetween returns the holidays between start and end inclusive, both at midnight of the same year
+func (cal *Calendar) holidaysBetween(start, end time.Time) []Holiday {
+	var named []Holiday
+	for _, h := range cal.GetHolidaysNamed(start.Year()) {
+		if !h.Date.Before(start) && !h.Date.After(end) {
+			named = append(named, h)
+		}
+	}
+	caldavHolidays, err := cal.caldavHolidays(start, end)
+	if err != nil {
+		cal.logger.Errorf("unable to check holidays from caldav: %v", err)
+	}
+	holidays := uniqueHolidays(named, caldavHolidays)
+	if holidays == nil {
+		holidays = make([]Holiday, 0)
+	}
+	return holidays
+}
+
+// PreviousHoliday returns the last holiday, national or from CalDAV, at or before from. A zero time is returned if no
+// holiday is found within a year.
+func (cal *Calendar) PreviousHoliday(from time.Time) (time.Time, string) {
+	day := cal.midnight(from)
+	horizon := day.AddDate(0, 0, -holidaySearchHorizon)
+
+	previous := Holiday{Date: horizon}
+	for year := day.Year(); year >= horizon.Year(); year-- {
+		for _, h := range cal.GetHolidaysNamed(year) {
+			if !h.Date.After(day) && h.Date.After(previous.Date) {
+				previous = h
+			}
+		}
+	}
+
+	caldavHolidays, err := cal.caldavHolidays(previous.Date, day)
 	if err != nil {
-		zap.S().Errorf("unable to check holidays from caldav: %v", err)
+		cal.logger.Errorf("unable to check holidays from caldav: %v", err)
 	}
-	return h[day] || caldavHolidays
+	if len(caldavHolidays) > 0 && caldavHolidays[len(caldavHolidays)-1].Date.After(previous.Date) {
+		previous = caldavHolidays[len(caldavHolidays)-1]
+	}
+
+	if !previous.Date.After(horizon) {
+		return time.Time{}, ""
+	}
+	return previous.Date, previous.Name
 }
 
 func (cal *Calendar) IsWorkingDay(date time.Time) bool {
-	return !cal.IsHoliday(date) && date.Weekday() >= time.Monday && date.Weekday() <= time.Friday
+	if cal.bridgeDays && cal.IsBridgeDay(date) {
+		return false
+	}
+	return !cal.IsHoliday(date) && cal.IsWeekDay(date)
+}
+
+// WorkingDayStatus returns IsWorkingDay and IsBridgeDay of date, the bridge day status being computed once
+func (cal *Calendar) WorkingDayStatus(date time.Time) (working bool, bridge bool) {
+	bridge = cal.IsBridgeDay(date)
+	if cal.bridgeDays && bridge {
+		return false, bridge
+	}
+	return !cal.IsHoliday(date) && cal.IsWeekDay(date), bridge
+}
+
+// BridgeHoliday returns the holiday that makes date a bridge day ("pont").
+//
+// A bridge day is a working day sandwiched between a holiday and the weekend, so that taking it off extends the
+// weekend: with the default weekend, a Monday before a Tuesday holiday, or a Friday after a Thursday holiday (e.g. the
+// Friday after Ascension). The weekend is the configured one, see WithWeekend, WithWeekdays and WithSaturdayWorking.
+// Holidays include CalDAV ones.
+func (cal *Calendar) BridgeHoliday(date time.Time) (Holiday, bool) {
+	day := cal.midnight(date)
+	adjacent, ok := cal.bridgeAdjacent(day)
+	if !ok || cal.IsHoliday(day) {
+		return Holiday{}, false
+	}
+	return cal.HolidayAt(adjacent)
+}
+
+// bridgeAdjacent returns the day that makes day a bridge day if it's a holiday: the working day on the other side of
+// day than the weekend. A weekend day, or a working day with the weekend or working days on both sides, can't be a
+// bridge day.
+func (cal *Calendar) bridgeAdjacent(day time.Time) (time.Time, bool) {
+	if cal.isWeekend(day) {
+		return time.Time{}, false
+	}
+	previous, next := day.AddDate(0, 0, -1), day.AddDate(0, 0, 1)
+	switch {
+	case cal.isWeekend(next) && !cal.isWeekend(previous):
+		return previous, true
+	case cal.isWeekend(previous) && !cal.isWeekend(next):
+		return next, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// IsBridgeDay returns true if date is a bridge day, see BridgeHoliday
+func (cal *Calendar) IsBridgeDay(date time.Time) bool {
+	_, bridge := cal.BridgeHoliday(date)
+	return bridge
 }
 
+// IsWorkingDayToday returns true if the current day, see WithClock, is a working day
 func (cal *Calendar) IsWorkingDayToday() bool {
-	return cal.IsWorkingDay(time.Now())
+	return cal.IsWorkingDay(cal.Now())
 }
 
+// WorkingDaysInMonth returns each working day of the month, at midnight in the calendar location
+func (cal *Calendar) WorkingDaysInMonth(year int, month time.Month) []time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, cal.Location)
+	return cal.WorkingDays(first, first.AddDate(0, 1, -1))
+}
+
+// NthWorkingDayOfMonth returns the nth working day of the month, n starting at 1, and false if the month has fewer
+// than n working days
+func (cal *Calendar) NthWorkingDayOfMonth(year int, month time.Month, n int) (time.Time, bool) {
+	days := cal.WorkingDaysInMonth(year, month)
+	if n < 1 || n > len(days) {
+		return time.Time{}, false
+	}
+	return days[n-1], true
+}
+
+// WorkingDays returns each working day between start and end inclusive, at midnight in the calendar location. CalDAV
+// holidays are read at once, see ForRange.
+func (cal *Calendar) WorkingDays(start, end time.Time) []time.Time {
+	var days []time.Time
+	last := cal.midnight(end)
+	c := cal.ForRange(start, end)
+	for day := cal.midnight(start); !day.After(last); day = day.AddDate(0, 0, 1) {
+		if c.IsWorkingDay(day) {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+// CountWorkingDays returns the number of working days between start and end inclusive
+func (cal *Calendar) CountWorkingDays(start, end time.Time) int {
+	return len(cal.WorkingDays(start, end))
+}
+
+// WorkingDaysStatus returns whether each of dates is a working day, see IsWorkingDay, in the same order. CalDAV
+// holidays are read with a single query spanning the earliest to the latest date, see ForRange.
+func (cal *Calendar) WorkingDaysStatus(dates []time.Time) []bool {
+	status := make([]bool, len(dates))
+	if len(dates) == 0 {
+		return status
+	}
+	first, last := dates[0], dates[0]
+	for _, d := range dates[1:] {
+		if d.Before(first) {
+			first = d
+		}
+		if d.After(last) {
+			last = d
+		}
+	}
+	c := cal.ForRange(first, last)
+	for i, d := range dates {
+		status[i] = c.IsWorkingDay(d)
+	}
+	return status
+}
+
+// IsWeekDay returns true if day isn't a weekend day, see WithWeekend
 func (cal *Calendar) IsWeekDay(day time.Time) bool {
-	return day.Weekday() >= time.Monday && day.Weekday() <= time.Friday
+	return !cal.isWeekend(day)
+}
+
+// HasCaldav returns true if CalDAV holidays are read, see WithCaldav
+func (cal *Calendar) HasCaldav() bool {
+	return cal.cdav != nil
 }
 
 func (cal *Calendar) IsHolidaysFromCaldav(day time.Time) (bool, error) {
+	_, holiday, err := cal.GetHolidayNameFromCaldav(day)
+	return holiday, err
+}
+
+// eventInterval returns the start and the end of the event, the end being computed from the duration if missing.
+// Times are kept in the timezone of the event (TZID), UTC for date-only values (VALUE=DATE). Events from midnight to
+// midnight in their own timezone are all-day events, moved to the same days in loc; other events are compared with
+// the days of loc as instants.
+func eventInterval(evt *components.Event, loc *time.Location) (time.Time, time.Time, bool) {
+	if evt.DateStart == nil {
+		return time.Time{}, time.Time{}, false
+	}
+	start := evt.DateStart.NativeTime()
+	end := start
+	if evt.DateEnd != nil {
+		end = evt.DateEnd.NativeTime()
+	} else if evt.Duration != nil {
+		end = start.Add(evt.Duration.NativeDuration())
+	}
+	if isMidnight(start) && isMidnight(end) {
+		start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc)
+		end = time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, loc)
+	}
+	return start, end, true
+}
+
+// isMidnight returns true if t is a midnight in its own location
+func isMidnight(t time.Time) bool {
+	return t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0
+}
+
+// holidayInterval returns the interval of evt if it's a CalDAV holiday: it has a start and an end or a duration, its
+// summary matches the pattern, and it covers whole days if caldavAllDayOnly is set. Malformed events are skipped.
+func (cal *Calendar) holidayInterval(evt *components.Event) (time.Time, time.Time, bool) {
+	if evt == nil {
+		return time.Time{}, time.Time{}, false
+	}
+	if evt.DateStart == nil || (evt.DateEnd == nil && evt.Duration == nil) {
+		cal.logger.Debugf("ignore caldav event '%v' without start or end", evt.UID)
+		return time.Time{}, time.Time{}, false
+	}
+	if !cal.matchesSummary(evt.Summary) {
+		return time.Time{}, time.Time{}, false
+	}
+	start, end, ok := eventInterval(evt, cal.Location)
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+	if cal.caldavAllDayOnly && !(start.Equal(cal.midnight(start)) && end.Equal(cal.midnight(end)) && !end.Before(start)) {
+		return time.Time{}, time.Time{}, false
+	}
+	return start, end, true
+}
+
+// queryCaldav returns the events matching query, capped to caldavMaxEvents, tried caldavQueryAttempts times. It isn't
+// queried nor retried while the CalDAV server rate limit pauses queries.
+func (cal *Calendar) queryCaldav(query *entities.CalendarQuery) ([]*components.Event, error) {
+	if until, paused := cal.caldavPausedUntil(); paused {
+		return nil, fmt.Errorf("%w, paused until %v", ErrRateLimited, until)
+	}
+	var events []*components.Event
+	err := retry.Do(
+		func() error {
+			var err error
+			events, err = cal.cdav.QueryEvents(cal.caldavPath, query)
+			return err
+		},
+		retry.RetryIf(func(err error) bool {
+			_, paused := cal.caldavPausedUntil()
+			return !paused && !errors.Is(err, ErrRateLimited)
+		}),
+		retry.OnRetry(func(n uint, err error) {
+			if n+1 < cal.caldavQueryAttempts {
+				cal.logger.Warnf("caldav query failed on attempt %d, retry: %v", n+1, err)
+			}
+		}),
+		retry.Attempts(cal.caldavQueryAttempts),
+		retry.Delay(caldavQueryRetryDelay),
+		retry.DelayType(retry.BackOffDelay),
+		retry.MaxDelay(caldavQueryMaxRetryDelay),
+		retry.LastErrorOnly(true),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if cal.caldavMaxEvents > 0 && len(events) > cal.caldavMaxEvents {
+		cal.logger.Warnf("caldav query returned %d events, only %d are scanned, check caldav path '%v'", len(events), cal.caldavMaxEvents, cal.caldavPath)
+		events = events[:cal.caldavMaxEvents]
+	}
+	return events, nil
+}
+
+// caldavPausedUntil returns the end of the CalDAV rate limit pause, true if queries are paused
+func (cal *Calendar) caldavPausedUntil() (time.Time, bool) {
+	if p, ok := cal.cdav.(pausable); ok {
+		return p.pausedUntil()
+	}
+	return time.Time{}, false
+}
+
+// matchesSummary returns true if summary contains any of the summary patterns. No pattern matches nothing, so that
+// a missing pattern doesn't turn every CalDAV event into a holiday.
+func (cal *Calendar) matchesSummary(summary string) bool {
+	summary = cal.normalizeSummary(summary)
+	for _, pattern := range cal.caldavSummaryPatterns {
+		if p := cal.normalizeSummary(pattern); p != "" && strings.Contains(summary, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeSummary prepares a summary or a pattern for matching, see WithCaldavNormalize and WithCaldavCaseInsensitive
+func (cal *Calendar) normalizeSummary(summary string) string {
+	if cal.caldavNormalize {
+		// decompose accented characters to remove their combining marks, then recompose the remaining ones
+		t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+		if folded, _, err := transform.String(t, summary); err == nil {
+			summary = folded
+		}
+		summary = strings.TrimSpace(summary)
+	}
+	if cal.caldavCaseInsensitive {
+		summary = strings.ToLower(summary)
+	}
+	return summary
+}
+
+// coversDay returns true if the event between start and end covers day, day being a midnight in cal.Location. The end
+// is exclusive, as DTEND in iCalendar: an all-day event ending on a Saturday doesn't cover that Saturday. An event
+// ending when it starts covers its first day.
+func (cal *Calendar) coversDay(start, end, day time.Time) bool {
+	first := cal.midnight(start)
+	return !day.Before(first) && (day.Equal(first) || day.Before(end))
+}
+
+// caldavWindow returns the CalDAV query window, in UTC, of the local days between start and end, inclusive
+func (cal *Calendar) caldavWindow(start, end time.Time) (time.Time, time.Time) {
+	start, end = cal.midnight(start), cal.midnight(end).AddDate(0, 0, 1)
+	return start.Add(-cal.caldavWindowMargin).UTC(), end.Add(cal.caldavWindowMargin).UTC()
+}
+
+// caldavHolidays returns the days between start and end, inclusive, covered by a CalDAV event matching the summary
+// pattern, sorted by date
+func (cal *Calendar) caldavHolidays(start, end time.Time) ([]Holiday, error) {
+	entries, err := cal.caldavHolidayEntries(start, end)
+	if err != nil {
+		return nil, err
+	}
+	holidays := make([]Holiday, 0, len(entries))
+	for d, entry := range entries {
+		holidays = append(holidays, Holiday{Date: d, Name: entry.Name, Source: SourceCaldav})
+	}
+	sort.Slice(holidays, func(i, j int) bool {
+		return holidays[i].Date.Before(holidays[j].Date)
+	})
+	return holidays, nil
+}
+
+// CaldavHolidayDays returns the set of days between start and end, inclusive, covered by a CalDAV event matching the
+// summary pattern. CalDAV is queried once for the whole range, instead of once per day with IsHolidaysFromCaldav.
+func (cal *Calendar) CaldavHolidayDays(start, end time.Time) (map[time.Time]bool, error) {
+	entries, err := cal.caldavHolidayEntries(start, end)
+	if err != nil {
+		return nil, err
+	}
+	days := make(map[time.Time]bool, len(entries))
+	for d := range entries {
+		days[d] = true
+	}
+	return days, nil
+}
+
+// caldavDays are the CalDAV holidays of the days between start and end, inclusive, read with a single query
+type caldavDays struct {
+	start   time.Time
+	end     time.Time
+	entries map[time.Time]caldavEntry
+	modTime time.Time
+	modOk   bool
+}
+
+// ForRange returns a copy of the calendar whose CalDAV holidays between start and end, inclusive, are read at once: a
+// multi-day CalDAV event marks every covered day of the range as holiday, with a single CalDAV query instead of one
+// per day. The day before start and the day after end are read too, for bridge days at the edges of the range.
+// On CalDAV error, the error is logged and cal is returned, querying CalDAV per day.
+func (cal *Calendar) ForRange(start, end time.Time) *Calendar {
 	if cal.cdav == nil {
-		return false, nil
+		return cal
 	}
-	query, err := entities.NewEventRangeQuery(day.UTC(), day.UTC().Add(23*time.Hour+59*time.Minute))
+	first, last := cal.midnight(start).AddDate(0, 0, -1), cal.midnight(end).AddDate(0, 0, 1)
+	events, err := cal.caldavEvents(first, last)
 	if err != nil {
-		return false, fmt.Errorf("unable to build events range query: %v", err)
+		cal.logger.Warnf("unable to read caldav holidays between %v and %v, read them per day: %v", first, last, err)
+		return cal
 	}
-	events, err := cal.cdav.QueryEvents(cal.caldavPath, query)
+	modTime, modOk := eventsModTime(events)
+	c := *cal
+	c.caldavDays = &caldavDays{
+		start:   first,
+		end:     last,
+		entries: cal.holidayEntriesOf(events, first, last),
+		modTime: modTime,
+		modOk:   modOk,
+	}
+	return &c
+}
+
+// CaldavModTime returns the latest modification, as of LAST-MODIFIED or else DTSTAMP, of the CalDAV events of the
+// range read by ForRange. false is returned if the calendar isn't a ForRange copy or an event has no modification
+// time. A deleted event doesn't change the modification time.
+func (cal *Calendar) CaldavModTime() (time.Time, bool) {
+	if cal.caldavDays == nil {
+		return time.Time{}, false
+	}
+	return cal.caldavDays.modTime, cal.caldavDays.modOk
+}
+
+// eventsModTime returns the latest modification of events, false if one of them has no modification time
+func eventsModTime(events []*components.Event) (time.Time, bool) {
+	var latest time.Time
+	for _, evt := range events {
+		var modTime time.Time
+		switch {
+		case evt.LastModified != nil:
+			modTime = evt.LastModified.NativeTime()
+		case evt.DateStamp != nil:
+			modTime = evt.DateStamp.NativeTime()
+		default:
+			return time.Time{}, false
+		}
+		if modTime.After(latest) {
+			latest = modTime
+		}
+	}
+	return latest, true
+}
+
+// caldavHolidayEntries returns the holiday entry of each day between start and end, inclusive, covered by a CalDAV
+// event matching the summary pattern, with a single CalDAV query. The status of each day of the range is cached, so
+// that GetHolidayNameFromCaldav doesn't query CalDAV again for these days. CalDAV isn't queried when the days are
+// within the range read by ForRange.
+func (cal *Calendar) caldavHolidayEntries(start, end time.Time) (map[time.Time]caldavEntry, error) {
+	if cal.cdav == nil {
+		return nil, nil
+	}
+	start, end = cal.midnight(start), cal.midnight(end)
+	if d := cal.caldavDays; d != nil && !start.Before(d.start) && !end.After(d.end) {
+		entries := make(map[time.Time]caldavEntry)
+		for day, entry := range d.entries {
+			if !day.Before(start) && !day.After(end) {
+				entries[day] = entry
+			}
+		}
+		return entries, nil
+	}
+	events, err := cal.caldavEvents(start, end)
+	if err != nil {
+		return nil, err
+	}
+	return cal.holidayEntriesOf(events, start, end), nil
+}
+
+// caldavEvents returns the CalDAV events of the days between start and end, inclusive, with a single CalDAV query
+func (cal *Calendar) caldavEvents(start, end time.Time) ([]*components.Event, error) {
+	query, err := entities.NewEventRangeQuery(cal.caldavWindow(start, end))
+	if err != nil {
+		return nil, fmt.Errorf("unable to build events range query: %v", err)
+	}
+	events, err := cal.queryCaldav(query)
 	if err != nil {
-		return false, fmt.Errorf("unable list events from caldav: %v", err)
+		return nil, fmt.Errorf("unable list events from caldav: %w", err)
+	}
+	return events, nil
+}
+
+// holidayEntriesOf returns the holiday entry of each day between start and end, inclusive, covered by one of events
+// matching the summary pattern, and caches the status of each day of the range
+func (cal *Calendar) holidayEntriesOf(events []*components.Event, start, end time.Time) map[time.Time]caldavEntry {
+	fetched := time.Now()
+	start, end = cal.midnight(start), cal.midnight(end).AddDate(0, 0, 1)
+	holidays := make(map[time.Time]caldavEntry)
+	for _, evt := range events {
+		evtStart, evtEnd, ok := cal.holidayInterval(evt)
+		if !ok {
+			continue
+		}
+		for d := cal.midnight(evtStart); d.Before(end) && cal.coversDay(evtStart, evtEnd, d); d = d.AddDate(0, 0, 1) {
+			if _, ok := holidays[d]; !ok && !d.Before(start) {
+				holidays[d] = cal.caldavHolidayEntry(evt, fetched)
+			}
+		}
+	}
+
+	entries := make(map[time.Time]caldavEntry)
+	for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
+		entry, ok := holidays[d]
+		if !ok {
+			entry = caldavEntry{Fetched: fetched}
+		}
+		entries[d] = entry
+	}
+	cal.caldavCache.setDays(entries)
+	return holidays
+}
+
+// caldavHolidayEntry returns the holiday entry of evt, a CalDAV event matching the summary pattern
+func (cal *Calendar) caldavHolidayEntry(evt *components.Event, fetched time.Time) caldavEntry {
+	return caldavEntry{
+		Name:    cal.caldavNameExtractor(evt.Summary),
+		Holiday: true,
+		Fetched: fetched,
+		UID:     evt.UID,
+		Summary: evt.Summary,
+	}
+}
+
+// GetHolidayNameFromCaldav returns the name of the first CalDAV event matching the summary pattern for the day, as
+// returned by the configured name extractor.
+func (cal *Calendar) GetHolidayNameFromCaldav(day time.Time) (string, bool, error) {
+	entry, err := cal.caldavEntryAt(day)
+	return entry.Name, entry.Holiday, err
+}
+
+// CaldavEvent identifies the CalDAV event that makes a day a holiday
+type CaldavEvent struct {
+	UID     string `json:"uid"`
+	Summary string `json:"summary"`
+}
+
+// CaldavEventAt returns the first CalDAV event matching the summary pattern for the day, nil if the day isn't a
+// CalDAV holiday, to find which event made it a holiday
+func (cal *Calendar) CaldavEventAt(day time.Time) (*CaldavEvent, error) {
+	entry, err := cal.caldavEntryAt(day)
+	if err != nil || !entry.Holiday {
+		return nil, err
+	}
+	return &CaldavEvent{UID: entry.UID, Summary: entry.Summary}, nil
+}
+
+// caldavEntryAt returns the CalDAV holiday entry of the day, from the range read by ForRange, the cache, or a CalDAV
+// query, falling back to a stale cached entry on CalDAV error
+func (cal *Calendar) caldavEntryAt(day time.Time) (caldavEntry, error) {
+	if cal.cdav == nil {
+		return caldavEntry{}, nil
+	}
+	key := cal.midnight(day)
+	if d := cal.caldavDays; d != nil && !key.Before(d.start) && !key.After(d.end) {
+		return d.entries[key], nil
+	}
+	if entry, ok := cal.caldavCache.get(key); ok {
+		return entry, nil
+	}
+	query, err := entities.NewEventRangeQuery(cal.caldavWindow(day, day))
+	if err != nil {
+		return caldavEntry{}, fmt.Errorf("unable to build events range query: %v", err)
+	}
+	events, err := cal.queryCaldav(query)
+	if err != nil {
+		if entry, ok := cal.caldavCache.stale(key); ok {
+			cal.logger.Warnf("unable list events from caldav, use cached status fetched at %v: %v", entry.Fetched, err)
+			return entry, nil
+		}
+		return caldavEntry{}, fmt.Errorf("unable list events from caldav: %w", err)
 	}
 
+	entry := caldavEntry{Fetched: time.Now()}
 	for _, evt := range events {
-		if strings.Contains(evt.Summary, cal.caldavSummaryPattern) {
-			return true, nil
+		if evtStart, evtEnd, ok := cal.holidayInterval(evt); ok && cal.coversDay(evtStart, evtEnd, key) {
+			entry = cal.caldavHolidayEntry(evt, entry.Fetched)
+			break
 		}
 	}
-	return false, nil
+	cal.caldavCache.set(key, entry)
+	return entry, nil
 }
@@ -1,9 +1,18 @@
 package calendar
 
 import (
+	"encoding/xml"
+	"errors"
+	"fmt"
 	"github.com/dolanor/caldav-go/caldav/entities"
+	"github.com/dolanor/caldav-go/icalendar"
 	"github.com/dolanor/caldav-go/icalendar/components"
 	"github.com/dolanor/caldav-go/icalendar/values"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 )
@@ -31,6 +40,121 @@ func TestCalendar_GetEasterDay(t *testing.T) {
 	}
 }
 
+func TestCalendar_YearRange(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	c := New(loc)
+
+	tests := []struct {
+		year int
+		want time.Time
+	}{
+		{year: -1},
+		{year: 0},
+		{year: MinYear - 1},
+		{year: MinYear, want: time.Date(MinYear, time.April, 10, 0, 0, 0, 0, loc)},
+		{year: MaxYear, want: time.Date(MaxYear, time.March, 28, 0, 0, 0, 0, loc)},
+		{year: MaxYear + 1},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.year), func(t *testing.T) {
+			if easter := c.GetEasterDay(tt.year); !easter.Equal(tt.want) {
+				t.Errorf("bad easter day, expected:%v ; actual:%v", tt.want, easter)
+			}
+			holidays := c.GetHolidaysNamed(tt.year)
+			if ValidYear(tt.year) != (len(holidays) == 11) || (!ValidYear(tt.year) && len(holidays) != 0) {
+				t.Errorf("bad holidays of year %d: %v", tt.year, holidays)
+			}
+			if !ValidYear(tt.year) && (c.IsHoliday(time.Date(tt.year, time.January, 1, 0, 0, 0, 0, loc)) || len(*c.GetHolidays(tt.year)) != 0) {
+				t.Errorf("year %d out of range should not have holidays", tt.year)
+			}
+		})
+	}
+}
+
+func TestCalendar_GetOrthodoxEasterDay(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+
+	easterDays := []time.Time{
+		time.Date(2008, time.April, 27, 0, 0, 0, 0, loc),
+		time.Date(2021, time.May, 2, 0, 0, 0, 0, loc),
+		time.Date(2023, time.April, 16, 0, 0, 0, 0, loc),
+		time.Date(2024, time.May, 5, 0, 0, 0, 0, loc),
+		time.Date(2025, time.April, 20, 0, 0, 0, 0, loc),
+	}
+
+	c := New(loc)
+
+	for _, d := range easterDays {
+		easter := c.GetOrthodoxEasterDay(d.Year())
+		if easter != d {
+			t.Errorf("bad date for year %d, expected:%v ; actual:%v", d.Year(), d, easter)
+		}
+	}
+}
+
+func TestCalendar_GetEasterDay_Midnight(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	c := New(loc)
+
+	for year := 2000; year <= 2040; year++ {
+		easter := c.GetEasterDay(year)
+		if easter.Hour() != 0 || easter.Minute() != 0 || easter.Second() != 0 || easter.Nanosecond() != 0 {
+			t.Errorf("easter day for year %d should be at midnight: %v", year, easter)
+		}
+		if easter.Location() != loc {
+			t.Errorf("easter day for year %d should be in %v: %v", year, loc, easter.Location())
+		}
+	}
+}
+
+func TestCalendar_MovableFeasts(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	c := New(loc)
+
+	tests := []struct {
+		name  string
+		feast func(year int) time.Time
+		year  int
+		want  time.Time
+	}{
+		{"MardiGras 2023", c.MardiGras, 2023, time.Date(2023, time.February, 21, 0, 0, 0, 0, loc)},
+		{"MardiGras 2024", c.MardiGras, 2024, time.Date(2024, time.February, 13, 0, 0, 0, 0, loc)},
+		{"MardiGras 2025", c.MardiGras, 2025, time.Date(2025, time.March, 4, 0, 0, 0, 0, loc)},
+		{"AshWednesday 2023", c.AshWednesday, 2023, time.Date(2023, time.February, 22, 0, 0, 0, 0, loc)},
+		{"AshWednesday 2024", c.AshWednesday, 2024, time.Date(2024, time.February, 14, 0, 0, 0, 0, loc)},
+		{"AshWednesday 2025", c.AshWednesday, 2025, time.Date(2025, time.March, 5, 0, 0, 0, 0, loc)},
+		{"AscensionDay 2023", c.AscensionDay, 2023, time.Date(2023, time.May, 18, 0, 0, 0, 0, loc)},
+		{"AscensionDay 2024", c.AscensionDay, 2024, time.Date(2024, time.May, 9, 0, 0, 0, 0, loc)},
+		{"AscensionDay 2025", c.AscensionDay, 2025, time.Date(2025, time.May, 29, 0, 0, 0, 0, loc)},
+		{"PentecostSunday 2023", c.PentecostSunday, 2023, time.Date(2023, time.May, 28, 0, 0, 0, 0, loc)},
+		{"PentecostSunday 2024", c.PentecostSunday, 2024, time.Date(2024, time.May, 19, 0, 0, 0, 0, loc)},
+		{"PentecostSunday 2025", c.PentecostSunday, 2025, time.Date(2025, time.June, 8, 0, 0, 0, 0, loc)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.feast(tt.year); got != tt.want {
+				t.Errorf("bad date for year %d, expected:%v ; actual:%v", tt.year, tt.want, got)
+			}
+		})
+	}
+}
+
 func TestCalendar_GetHolidays(t *testing.T) {
 	loc, err := time.LoadLocation("Europe/Paris")
 	if err != nil {
@@ -44,6 +168,7 @@ func TestCalendar_GetHolidays(t *testing.T) {
 		time.Date(2020, time.May, 1, 0, 0, 0, 0, loc):       true,
 		time.Date(2020, time.May, 8, 0, 0, 0, 0, loc):       true,
 		time.Date(2020, time.May, 21, 0, 0, 0, 0, loc):      true,
+		time.Date(2020, time.June, 1, 0, 0, 0, 0, loc):      true,
 		time.Date(2020, time.July, 14, 0, 0, 0, 0, loc):     true,
 		time.Date(2020, time.August, 15, 0, 0, 0, 0, loc):   true,
 		time.Date(2020, time.November, 1, 0, 0, 0, 0, loc):  true,
@@ -76,6 +201,7 @@ func TestCalendar_GetHolidaysSet(t *testing.T) {
 		time.Date(2020, time.May, 1, 0, 0, 0, 0, loc),
 		time.Date(2020, time.May, 8, 0, 0, 0, 0, loc),
 		time.Date(2020, time.May, 21, 0, 0, 0, 0, loc),
+		time.Date(2020, time.June, 1, 0, 0, 0, 0, loc),
 		time.Date(2020, time.July, 14, 0, 0, 0, 0, loc),
 		time.Date(2020, time.August, 15, 0, 0, 0, 0, loc),
 		time.Date(2020, time.November, 1, 0, 0, 0, 0, loc),
@@ -108,6 +234,7 @@ func TestCalendar_IsHolidays(t *testing.T) {
 		time.Date(2020, time.May, 1, 0, 0, 0, 0, loc),
 		time.Date(2020, time.May, 8, 0, 0, 0, 0, loc),
 		time.Date(2020, time.May, 21, 0, 0, 0, 0, loc),
+		time.Date(2020, time.June, 1, 0, 0, 0, 0, loc),
 		time.Date(2020, time.July, 14, 0, 0, 0, 0, loc),
 		time.Date(2020, time.August, 15, 0, 0, 0, 0, loc),
 		time.Date(2020, time.November, 1, 0, 0, 0, 0, loc),
@@ -130,6 +257,161 @@ func TestCalendar_IsHolidays(t *testing.T) {
 	}
 }
 
+func TestCalendar_GetHolidaysNamed_EasterMonday(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	c := New(loc)
+
+	// Lundi de Pâques is the day after Easter Sunday, not Easter itself
+	easter := time.Date(2024, time.March, 31, 0, 0, 0, 0, loc)
+	want := time.Date(2024, time.April, 1, 0, 0, 0, 0, loc)
+	found := false
+	for _, h := range c.GetHolidaysNamed(2024) {
+		if h.Name == "Lundi de Pâques" {
+			found = true
+			if h.Date != want {
+				t.Errorf("bad Easter Monday date, expected:%v ; actual:%v", want, h.Date)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("Easter Monday missing from 2024 holidays")
+	}
+	if !c.IsHoliday(want) {
+		t.Errorf("%v should be a holiday", want)
+	}
+	if c.IsHoliday(easter) {
+		t.Errorf("Easter Sunday %v should not be a holiday", easter)
+	}
+}
+
+func TestCalendar_GetHolidaysNamed_PentecostMonday(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+
+	tests := []struct {
+		name string
+		opts []Option
+		year int
+		want time.Time
+		ok   bool
+	}{
+		{
+			name: "2024 by default",
+			year: 2024,
+			want: time.Date(2024, time.May, 20, 0, 0, 0, 0, loc),
+			ok:   true,
+		},
+		{
+			name: "2025 by default",
+			year: 2025,
+			want: time.Date(2025, time.June, 9, 0, 0, 0, 0, loc),
+			ok:   true,
+		},
+		{
+			name: "2024 disabled",
+			opts: []Option{WithPentecostMonday(false)},
+			year: 2024,
+			want: time.Date(2024, time.May, 20, 0, 0, 0, 0, loc),
+			ok:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(loc, tt.opts...)
+			found := false
+			for _, h := range c.GetHolidaysNamed(tt.year) {
+				if h.Name == "Lundi de Pentecôte" {
+					found = true
+					if h.Date != tt.want {
+						t.Errorf("bad Pentecost Monday date, expected:%v ; actual:%v", tt.want, h.Date)
+					}
+				}
+			}
+			if found != tt.ok {
+				t.Errorf("Pentecost Monday present = %v, want %v", found, tt.ok)
+			}
+			if c.IsHoliday(tt.want) != tt.ok {
+				t.Errorf("IsHoliday(%v) = %v, want %v", tt.want, !tt.ok, tt.ok)
+			}
+		})
+	}
+}
+
+func TestCalendar_GetHolidaysNamed_Region(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	goodFriday := time.Date(2024, time.March, 29, 0, 0, 0, 0, loc)
+	saintStephen := time.Date(2024, time.December, 26, 0, 0, 0, 0, loc)
+
+	metropole := New(loc)
+	if metropole.IsHoliday(goodFriday) || metropole.IsHoliday(saintStephen) {
+		t.Error("Vendredi saint and Saint Étienne should not be holidays in metropole")
+	}
+
+	alsaceMoselle := New(loc, WithRegion(RegionAlsaceMoselle))
+	if !alsaceMoselle.IsHoliday(goodFriday) || !alsaceMoselle.IsHoliday(saintStephen) {
+		t.Error("Vendredi saint and Saint Étienne should be holidays in alsace-moselle")
+	}
+	holidays := alsaceMoselle.GetHolidaysNamed(2024)
+	if len(holidays) != len(metropole.GetHolidaysNamed(2024))+2 {
+		t.Errorf("bad number of holidays in alsace-moselle: %d", len(holidays))
+	}
+	for i := 1; i < len(holidays); i++ {
+		if !holidays[i-1].Date.Before(holidays[i].Date) {
+			t.Errorf("holidays should be sorted by date: %v", holidays)
+		}
+	}
+}
+
+func TestCalendar_GetHolidaysNamed_ExtraHolidays(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	national := len(New(loc).GetHolidaysNamed(2024))
+
+	tests := []struct {
+		name string
+		opts []Option
+		want int
+	}{
+		{"Good Friday", []Option{WithGoodFriday()}, national + 1},
+		{"Saint Stephen", []Option{WithSaintStephen()}, national + 1},
+		{"Both", []Option{WithGoodFriday(), WithSaintStephen()}, national + 2},
+		{"Alsace-Moselle region", []Option{WithRegion(RegionAlsaceMoselle), WithGoodFriday(), WithSaintStephen()}, national + 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(loc, tt.opts...)
+			if got := len(c.GetHolidaysNamed(2024)); got != tt.want {
+				t.Errorf("bad number of holidays, %d but %d are expected", got, tt.want)
+			}
+		})
+	}
+
+	c := New(loc, WithGoodFriday())
+	for _, year := range []int{2023, 2024, 2025} {
+		goodFriday := c.GetEasterDay(year).AddDate(0, 0, -2)
+		if goodFriday.Weekday() != time.Friday {
+			t.Errorf("Good Friday %v should be a friday", goodFriday)
+		}
+		if name, _ := c.HolidayName(goodFriday); name != "Vendredi saint" {
+			t.Errorf("%v should be Vendredi saint, got %v", goodFriday, name)
+		}
+	}
+}
+
 func TestCalendar_IsWorkingDay(t *testing.T) {
 	loc, err := time.LoadLocation("Europe/Paris")
 	if err != nil {
@@ -168,113 +450,2087 @@ func TestCalendar_IsWorkingDay(t *testing.T) {
 	}
 }
 
-type MockCaldav struct {
-	events []*components.Event
-}
+func TestCalendar_WorkingDaysInMonth(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	c := New(loc)
 
-func (m *MockCaldav) QueryEvents(_ string, _ *entities.CalendarQuery) ([]*components.Event, error) {
-	return m.events, nil
+	tests := []struct {
+		name     string
+		year     int
+		month    time.Month
+		want     int
+		excluded []time.Time
+	}{
+		{
+			name:  "February in a leap year",
+			year:  2024,
+			month: time.February,
+			want:  21,
+			excluded: []time.Time{
+				time.Date(2024, time.February, 24, 0, 0, 0, 0, loc),
+				time.Date(2024, time.February, 25, 0, 0, 0, 0, loc),
+			},
+		},
+		{
+			name:  "May with holidays",
+			year:  2024,
+			month: time.May,
+			want:  19,
+			excluded: []time.Time{
+				time.Date(2024, time.May, 1, 0, 0, 0, 0, loc),
+				time.Date(2024, time.May, 8, 0, 0, 0, 0, loc),
+				time.Date(2024, time.May, 9, 0, 0, 0, 0, loc),
+				time.Date(2024, time.May, 20, 0, 0, 0, 0, loc),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			days := c.WorkingDaysInMonth(tt.year, tt.month)
+			if len(days) != tt.want {
+				t.Errorf("bad number of working days, %d but %d are expected", len(days), tt.want)
+			}
+			for _, d := range days {
+				if d.Month() != tt.month || d.Hour() != 0 || d.Location() != loc {
+					t.Errorf("bad working day %v", d)
+				}
+				for _, e := range tt.excluded {
+					if d.Equal(e) {
+						t.Errorf("%v should not be a working day", d)
+					}
+				}
+			}
+			if tt.month == time.February && days[len(days)-1].Day() != 29 {
+				t.Errorf("29 february should be the last working day: %v", days[len(days)-1])
+			}
+		})
+	}
 }
 
-func TestCalendar_IsHolidaysFromCaldav(t *testing.T) {
+func TestCalendar_BridgeDays(t *testing.T) {
 	loc, err := time.LoadLocation("Europe/Paris")
 	if err != nil {
 		t.Errorf("unable to load time location: %v", err)
 		t.Fail()
 	}
-	type fields struct {
-		Location             *time.Location
-		cdav                 *MockCaldav
-		caldavPath           string
-		caldavSummaryPattern string
-	}
-	type args struct {
-		day time.Time
-	}
+
 	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		want    bool
-		wantErr bool
+		name        string
+		date        time.Time
+		wantBridge  bool
+		wantHoliday string
 	}{
 		{
-			name: "Holidays in events",
-			fields: fields{
-				Location: loc,
-				cdav: &MockCaldav{
-					events: []*components.Event{
-						{
-							UID:       "1",
-							DateStart: values.NewDateTime(time.Date(2022, time.April, 16, 0, 0, 0, 0, loc)),
-							DateEnd:   values.NewDateTime(time.Date(2022, time.April, 17, 0, 0, 0, 0, loc)),
-							Summary:   "Holidays",
-						},
-					},
-				},
-				caldavPath:           "my_calendar/",
-				caldavSummaryPattern: "Holidays",
-			},
-			args: args{
-				day: time.Date(2022, time.April, 16, 0, 0, 0, 0, loc),
-			},
-			want:    true,
-			wantErr: false,
+			name:        "Friday after Ascension",
+			date:        time.Date(2024, time.May, 10, 0, 0, 0, 0, loc),
+			wantBridge:  true,
+			wantHoliday: "Ascension",
 		},
 		{
-			name: "Not Holidays in events",
-			fields: fields{
-				Location: loc,
-				cdav: &MockCaldav{
-					events: []*components.Event{
-						{
-							UID:       "1",
-							DateStart: values.NewDateTime(time.Date(2022, time.April, 16, 0, 0, 0, 0, loc)),
-							DateEnd:   values.NewDateTime(time.Date(2022, time.April, 17, 0, 0, 0, 0, loc)),
-							Summary:   "Another event",
-						},
-					},
-				},
-				caldavPath:           "my_calendar/",
-				caldavSummaryPattern: "Holidays",
-			},
-			args: args{
-				day: time.Date(2022, time.April, 16, 0, 0, 0, 0, loc),
-			},
-			want:    false,
-			wantErr: false,
+			name:        "Monday before a Tuesday holiday",
+			date:        time.Date(2023, time.August, 14, 0, 0, 0, 0, loc),
+			wantBridge:  true,
+			wantHoliday: "Assomption",
 		},
 		{
-			name: "No events",
-			fields: fields{
-				Location:             loc,
-				cdav:                 &MockCaldav{},
-				caldavPath:           "my_calendar/",
-				caldavSummaryPattern: "Holidays",
-			},
-			args: args{
-				day: time.Date(2022, time.April, 15, 0, 0, 0, 0, loc),
-			},
-			want:    false,
-			wantErr: false,
+			name:       "Friday after a Wednesday holiday",
+			date:       time.Date(2024, time.May, 3, 0, 0, 0, 0, loc),
+			wantBridge: false,
+		},
+		{
+			name:       "Holiday",
+			date:       time.Date(2024, time.May, 9, 0, 0, 0, 0, loc),
+			wantBridge: false,
+		},
+		{
+			name:       "Ordinary Monday",
+			date:       time.Date(2024, time.May, 13, 0, 0, 0, 0, loc),
+			wantBridge: false,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			cal := New(
-				loc,
-				WithCaldav(tt.fields.cdav),
-				WithCaldavPath(tt.fields.caldavPath),
-				WithCaldavSummaryPattern(tt.fields.caldavSummaryPattern),
-			)
-			got, err := cal.IsHolidaysFromCaldav(tt.args.day)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("IsHolidaysFromCaldav() error = %v, wantErr %v", err, tt.wantErr)
-				return
+			c := New(loc)
+			h, bridge := c.BridgeHoliday(tt.date)
+			if bridge != tt.wantBridge || h.Name != tt.wantHoliday {
+				t.Errorf("BridgeHoliday() got = (%v, %v), want (%v, %v)", h.Name, bridge, tt.wantHoliday, tt.wantBridge)
 			}
-			if got != tt.want {
-				t.Errorf("IsHolidaysFromCaldav() got = %v, want %v", got, tt.want)
+			if c.IsWorkingDay(tt.date) == c.IsHoliday(tt.date) {
+				t.Errorf("%v working day status shouldn't depend on bridge days by default", tt.date)
+			}
+
+			c = New(loc, WithBridgeDays(true))
+			if tt.wantBridge && c.IsWorkingDay(tt.date) {
+				t.Errorf("bridge day %v should not be a working day", tt.date)
 			}
 		})
 	}
 }
+
+func TestCalendar_BridgeDays_Week(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	monToThu := WithWeekdays(time.Monday, time.Tuesday, time.Wednesday, time.Thursday)
+
+	// 2026: Ascension on Thursday 14 May, 14 July on a Tuesday, 11 November on a Wednesday
+	tests := []struct {
+		name        string
+		opts        []Option
+		date        time.Time
+		wantBridge  bool
+		wantHoliday string
+		wantWorking bool
+	}{
+		{
+			name:        "Friday after Ascension",
+			date:        time.Date(2026, time.May, 15, 0, 0, 0, 0, loc),
+			wantBridge:  true,
+			wantHoliday: "Ascension",
+		},
+		{
+			name: "Friday after Ascension, Monday to Thursday week",
+			opts: []Option{monToThu},
+			date: time.Date(2026, time.May, 15, 0, 0, 0, 0, loc),
+		},
+		{
+			name:        "Thursday after a Wednesday holiday, Monday to Thursday week",
+			opts:        []Option{monToThu},
+			date:        time.Date(2026, time.November, 12, 0, 0, 0, 0, loc),
+			wantBridge:  true,
+			wantHoliday: "Armistice 1918",
+		},
+		{
+			name:        "Monday before a Tuesday holiday, Monday to Thursday week",
+			opts:        []Option{monToThu},
+			date:        time.Date(2026, time.July, 13, 0, 0, 0, 0, loc),
+			wantBridge:  true,
+			wantHoliday: "Fête nationale",
+		},
+		{
+			name:        "Friday after Ascension, Saturday working",
+			opts:        []Option{WithSaturdayWorking(true)},
+			date:        time.Date(2026, time.May, 15, 0, 0, 0, 0, loc),
+			wantWorking: true,
+		},
+		{
+			name:        "Saturday after a Friday holiday, Saturday working",
+			opts:        []Option{WithSaturdayWorking(true)},
+			date:        time.Date(2026, time.May, 2, 0, 0, 0, 0, loc),
+			wantBridge:  true,
+			wantHoliday: "Fête du travail",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(loc, tt.opts...)
+			h, bridge := c.BridgeHoliday(tt.date)
+			if bridge != tt.wantBridge || h.Name != tt.wantHoliday {
+				t.Errorf("BridgeHoliday() got = (%v, %v), want (%v, %v)", h.Name, bridge, tt.wantHoliday, tt.wantBridge)
+			}
+			if got := c.IsBridgeDay(tt.date); got != tt.wantBridge {
+				t.Errorf("bad bridge day status, expected:%v ; actual:%v", tt.wantBridge, got)
+			}
+
+			c = New(loc, append(tt.opts, WithBridgeDays(true))...)
+			if working := c.IsWorkingDay(tt.date); working != tt.wantWorking {
+				t.Errorf("bad working day status with bridge days off, expected:%v ; actual:%v", tt.wantWorking, working)
+			}
+			if working := c.IsWorkingDayNational(tt.date); working != tt.wantWorking {
+				t.Errorf("bad national working day status with bridge days off, expected:%v ; actual:%v", tt.wantWorking, working)
+			}
+			if working, bridge := c.WorkingDayStatus(tt.date); working != tt.wantWorking || bridge != tt.wantBridge {
+				t.Errorf("bad working day status, expected:(%v, %v) ; actual:(%v, %v)", tt.wantWorking, tt.wantBridge, working, bridge)
+			}
+		})
+	}
+
+	// Standard week: 2 January, 15 May and 13 July. Monday to Thursday: 13 July and 12 November. Saturday working:
+	// 2 and 9 May, 13 July and 26 December.
+	stats := []struct {
+		name string
+		opts []Option
+		want int
+	}{
+		{name: "Standard week", want: 3},
+		{name: "Monday to Thursday week", opts: []Option{monToThu}, want: 2},
+		{name: "Saturday working", opts: []Option{WithSaturdayWorking(true)}, want: 4},
+	}
+	for _, tt := range stats {
+		t.Run(tt.name+" stats", func(t *testing.T) {
+			if got := New(loc, tt.opts...).YearStats(2026).BridgeDays; got != tt.want {
+				t.Errorf("bad bridge days count, expected:%v ; actual:%v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCalendar_NextHoliday(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	tests := []struct {
+		name     string
+		cdav     *MockCaldav
+		from     time.Time
+		wantDate time.Time
+		wantName string
+	}{
+		{
+			name:     "National holiday",
+			cdav:     &MockCaldav{},
+			from:     time.Date(2024, time.May, 2, 10, 0, 0, 0, loc),
+			wantDate: time.Date(2024, time.May, 8, 0, 0, 0, 0, loc),
+			wantName: "Victoire 1945",
+		},
+		{
+			name:     "Holiday today",
+			cdav:     &MockCaldav{},
+			from:     time.Date(2024, time.May, 8, 10, 0, 0, 0, loc),
+			wantDate: time.Date(2024, time.May, 8, 0, 0, 0, 0, loc),
+			wantName: "Victoire 1945",
+		},
+		{
+			name:     "Next year",
+			cdav:     &MockCaldav{},
+			from:     time.Date(2024, time.December, 26, 10, 0, 0, 0, loc),
+			wantDate: time.Date(2025, time.January, 1, 0, 0, 0, 0, loc),
+			wantName: "Jour de l'an",
+		},
+		{
+			name: "Caldav holiday",
+			cdav: &MockCaldav{
+				events: []*components.Event{
+					{
+						UID:       "1",
+						DateStart: values.NewDateTime(time.Date(2024, time.May, 6, 0, 0, 0, 0, loc)),
+						DateEnd:   values.NewDateTime(time.Date(2024, time.May, 7, 0, 0, 0, 0, loc)),
+						Summary:   "Holidays",
+					},
+				},
+			},
+			from:     time.Date(2024, time.May, 2, 10, 0, 0, 0, loc),
+			wantDate: time.Date(2024, time.May, 6, 0, 0, 0, 0, loc),
+			wantName: "Holidays",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(loc, WithCaldav(tt.cdav), WithCaldavSummaryPattern("Holidays"))
+			date, name := c.NextHoliday(tt.from)
+			if !date.Equal(tt.wantDate) || name != tt.wantName {
+				t.Errorf("NextHoliday() got = (%v, %v), want (%v, %v)", date, name, tt.wantDate, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestCalendar_UpcomingHolidays(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	cdav := &MockCaldav{
+		events: []*components.Event{
+			{
+				UID:       "1",
+				DateStart: values.NewDateTime(time.Date(2024, time.December, 30, 0, 0, 0, 0, loc)),
+				DateEnd:   values.NewDateTime(time.Date(2025, time.January, 1, 0, 0, 0, 0, loc)),
+				Summary:   "Holidays",
+			},
+		},
+	}
+	c := New(loc, WithCaldav(cdav), WithCaldavSummaryPattern("Holidays"))
+
+	tests := []struct {
+		name      string
+		from      time.Time
+		n         int
+		wantDates []time.Time
+		wantNames []string
+	}{
+		{
+			name: "Next year",
+			from: time.Date(2024, time.December, 20, 10, 0, 0, 0, loc),
+			n:    5,
+			wantDates: []time.Time{
+				time.Date(2024, time.December, 25, 0, 0, 0, 0, loc),
+				time.Date(2024, time.December, 30, 0, 0, 0, 0, loc),
+				time.Date(2024, time.December, 31, 0, 0, 0, 0, loc),
+				time.Date(2025, time.January, 1, 0, 0, 0, 0, loc),
+				time.Date(2025, time.April, 21, 0, 0, 0, 0, loc),
+			},
+			wantNames: []string{"Noël", "Holidays", "Holidays", "Jour de l'an", "Lundi de Pâques"},
+		},
+		{
+			name:      "Holiday today",
+			from:      time.Date(2024, time.December, 25, 10, 0, 0, 0, loc),
+			n:         1,
+			wantDates: []time.Time{time.Date(2024, time.December, 25, 0, 0, 0, 0, loc)},
+			wantNames: []string{"Noël"},
+		},
+		{
+			name: "None",
+			from: time.Date(2024, time.December, 20, 10, 0, 0, 0, loc),
+			n:    0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := c.UpcomingHolidays(tt.from, tt.n)
+			if len(got) != len(tt.wantDates) {
+				t.Fatalf("bad holidays count, expected:%v ; actual:%v (%v)", len(tt.wantDates), len(got), got)
+			}
+			for i, h := range got {
+				if !h.Date.Equal(tt.wantDates[i]) || h.Name != tt.wantNames[i] {
+					t.Errorf("bad holiday %d, expected:(%v, %v) ; actual:(%v, %v)", i, tt.wantDates[i], tt.wantNames[i], h.Date, h.Name)
+				}
+			}
+		})
+	}
+
+	if got := c.UpcomingHolidays(time.Date(2024, time.December, 20, 10, 0, 0, 0, loc), 1000); len(got) != MaxUpcomingHolidays {
+		t.Errorf("holidays count should be capped, expected:%v ; actual:%v", MaxUpcomingHolidays, len(got))
+	}
+}
+
+func TestCalendar_PreviousHoliday(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	tests := []struct {
+		name     string
+		cdav     *MockCaldav
+		from     time.Time
+		wantDate time.Time
+		wantName string
+	}{
+		{
+			name:     "National holiday",
+			cdav:     &MockCaldav{},
+			from:     time.Date(2024, time.May, 7, 10, 0, 0, 0, loc),
+			wantDate: time.Date(2024, time.May, 1, 0, 0, 0, 0, loc),
+			wantName: "Fête du travail",
+		},
+		{
+			name:     "Holiday today",
+			cdav:     &MockCaldav{},
+			from:     time.Date(2024, time.May, 8, 10, 0, 0, 0, loc),
+			wantDate: time.Date(2024, time.May, 8, 0, 0, 0, 0, loc),
+			wantName: "Victoire 1945",
+		},
+		{
+			name:     "Early January",
+			cdav:     &MockCaldav{},
+			from:     time.Date(2025, time.January, 6, 10, 0, 0, 0, loc),
+			wantDate: time.Date(2025, time.January, 1, 0, 0, 0, 0, loc),
+			wantName: "Jour de l'an",
+		},
+		{
+			name:     "Christmas",
+			cdav:     &MockCaldav{},
+			from:     time.Date(2024, time.December, 31, 10, 0, 0, 0, loc),
+			wantDate: time.Date(2024, time.December, 25, 0, 0, 0, 0, loc),
+			wantName: "Noël",
+		},
+		{
+			name: "Caldav holiday",
+			cdav: &MockCaldav{
+				events: []*components.Event{
+					{
+						UID:       "1",
+						DateStart: values.NewDateTime(time.Date(2024, time.May, 2, 0, 0, 0, 0, loc)),
+						DateEnd:   values.NewDateTime(time.Date(2024, time.May, 4, 0, 0, 0, 0, loc)),
+						Summary:   "Holidays",
+					},
+				},
+			},
+			from:     time.Date(2024, time.May, 7, 10, 0, 0, 0, loc),
+			wantDate: time.Date(2024, time.May, 3, 0, 0, 0, 0, loc),
+			wantName: "Holidays",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(loc, WithCaldav(tt.cdav), WithCaldavSummaryPattern("Holidays"))
+			date, name := c.PreviousHoliday(tt.from)
+			if !date.Equal(tt.wantDate) || name != tt.wantName {
+				t.Errorf("PreviousHoliday() got = (%v, %v), want (%v, %v)", date, name, tt.wantDate, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestCalendar_HolidayCount(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	tests := []struct {
+		name string
+		cdav *MockCaldav
+		want int
+	}{
+		{
+			name: "National holidays only",
+			cdav: &MockCaldav{},
+			want: 11,
+		},
+		{
+			name: "Caldav holidays spanning a national holiday",
+			cdav: &MockCaldav{
+				events: []*components.Event{
+					{
+						UID:       "1",
+						DateStart: values.NewDateTime(time.Date(2024, time.August, 12, 0, 0, 0, 0, loc)),
+						DateEnd:   values.NewDateTime(time.Date(2024, time.August, 19, 0, 0, 0, 0, loc)),
+						Summary:   "Holidays",
+					},
+				},
+			},
+			want: 17,
+		},
+		{
+			name: "Caldav holidays overlapping next year",
+			cdav: &MockCaldav{
+				events: []*components.Event{
+					{
+						UID:       "1",
+						DateStart: values.NewDateTime(time.Date(2024, time.December, 30, 0, 0, 0, 0, loc)),
+						DateEnd:   values.NewDateTime(time.Date(2025, time.January, 3, 0, 0, 0, 0, loc)),
+						Summary:   "Holidays",
+					},
+				},
+			},
+			want: 13,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(loc, WithCaldav(tt.cdav), WithCaldavSummaryPattern("Holidays"))
+			if got := c.HolidayCount(2024); got != tt.want {
+				t.Errorf("HolidayCount() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalendar_IsWorkingDayNational(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	cdav := &MockCaldav{
+		events: []*components.Event{
+			{
+				UID:       "1",
+				DateStart: values.NewDateTime(time.Date(2024, time.May, 6, 0, 0, 0, 0, loc)),
+				DateEnd:   values.NewDateTime(time.Date(2024, time.May, 8, 0, 0, 0, 0, loc)),
+				Summary:   "Holidays",
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		opts        []Option
+		date        time.Time
+		want        bool
+		wantWorking bool
+	}{
+		{name: "Caldav holiday", date: time.Date(2024, time.May, 6, 10, 0, 0, 0, loc), want: true, wantWorking: false},
+		{name: "National holiday", date: time.Date(2024, time.May, 8, 10, 0, 0, 0, loc), want: false, wantWorking: false},
+		{name: "Weekend", date: time.Date(2024, time.May, 11, 10, 0, 0, 0, loc), want: false, wantWorking: false},
+		{name: "Bridge day", opts: []Option{WithBridgeDays(true)}, date: time.Date(2024, time.May, 10, 10, 0, 0, 0, loc), want: false, wantWorking: false},
+		{name: "Bridge days disabled", date: time.Date(2024, time.May, 10, 10, 0, 0, 0, loc), want: true, wantWorking: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(loc, append([]Option{WithCaldav(cdav), WithCaldavSummaryPattern("Holidays")}, tt.opts...)...)
+			if got := c.IsWorkingDayNational(tt.date); got != tt.want {
+				t.Errorf("IsWorkingDayNational(%v) = %v, want %v", tt.date, got, tt.want)
+			}
+			if got := c.IsWorkingDay(tt.date); got != tt.wantWorking {
+				t.Errorf("IsWorkingDay(%v) = %v, want %v", tt.date, got, tt.wantWorking)
+			}
+		})
+	}
+
+	counting := &CountingCaldav{MockCaldav: *cdav}
+	c := New(loc, WithCaldav(counting), WithCaldavSummaryPattern("Holidays"), WithBridgeDays(true))
+	c.IsWorkingDayNational(time.Date(2024, time.May, 6, 10, 0, 0, 0, loc))
+	if counting.queries != 0 {
+		t.Errorf("IsWorkingDayNational() should not query caldav, %d queries", counting.queries)
+	}
+}
+
+type MockCaldav struct {
+	events []*components.Event
+}
+
+func (m *MockCaldav) QueryEvents(_ string, _ *entities.CalendarQuery) ([]*components.Event, error) {
+	return m.events, nil
+}
+
+func TestCalendar_IsHolidaysFromCaldav(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	type fields struct {
+		Location             *time.Location
+		cdav                 *MockCaldav
+		caldavPath           string
+		caldavSummaryPattern string
+	}
+	type args struct {
+		day time.Time
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "Holidays in events",
+			fields: fields{
+				Location: loc,
+				cdav: &MockCaldav{
+					events: []*components.Event{
+						{
+							UID:       "1",
+							DateStart: values.NewDateTime(time.Date(2022, time.April, 16, 0, 0, 0, 0, loc)),
+							DateEnd:   values.NewDateTime(time.Date(2022, time.April, 17, 0, 0, 0, 0, loc)),
+							Summary:   "Holidays",
+						},
+					},
+				},
+				caldavPath:           "my_calendar/",
+				caldavSummaryPattern: "Holidays",
+			},
+			args: args{
+				day: time.Date(2022, time.April, 16, 0, 0, 0, 0, loc),
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "Not Holidays in events",
+			fields: fields{
+				Location: loc,
+				cdav: &MockCaldav{
+					events: []*components.Event{
+						{
+							UID:       "1",
+							DateStart: values.NewDateTime(time.Date(2022, time.April, 16, 0, 0, 0, 0, loc)),
+							DateEnd:   values.NewDateTime(time.Date(2022, time.April, 17, 0, 0, 0, 0, loc)),
+							Summary:   "Another event",
+						},
+					},
+				},
+				caldavPath:           "my_calendar/",
+				caldavSummaryPattern: "Holidays",
+			},
+			args: args{
+				day: time.Date(2022, time.April, 16, 0, 0, 0, 0, loc),
+			},
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name: "No events",
+			fields: fields{
+				Location:             loc,
+				cdav:                 &MockCaldav{},
+				caldavPath:           "my_calendar/",
+				caldavSummaryPattern: "Holidays",
+			},
+			args: args{
+				day: time.Date(2022, time.April, 15, 0, 0, 0, 0, loc),
+			},
+			want:    false,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cal := New(
+				loc,
+				WithCaldav(tt.fields.cdav),
+				WithCaldavPath(tt.fields.caldavPath),
+				WithCaldavSummaryPattern(tt.fields.caldavSummaryPattern),
+			)
+			got, err := cal.IsHolidaysFromCaldav(tt.args.day)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("IsHolidaysFromCaldav() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("IsHolidaysFromCaldav() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalendar_GetHolidayNameFromCaldav(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	cdav := &MockCaldav{
+		events: []*components.Event{
+			{
+				UID:       "1",
+				DateStart: values.NewDateTime(time.Date(2022, time.December, 26, 0, 0, 0, 0, loc)),
+				DateEnd:   values.NewDateTime(time.Date(2022, time.December, 27, 0, 0, 0, 0, loc)),
+				Summary:   "Vacances - Noël",
+			},
+		},
+	}
+	day := time.Date(2022, time.December, 26, 0, 0, 0, 0, loc)
+
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{
+			name: "Default extractor",
+			opts: nil,
+			want: "Vacances - Noël",
+		},
+		{
+			name: "Custom extractor",
+			opts: []Option{
+				WithCaldavNameExtractor(func(summary string) string {
+					return strings.TrimSpace(strings.TrimPrefix(summary, "Vacances -"))
+				}),
+			},
+			want: "Noël",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option{
+				WithCaldav(cdav),
+				WithCaldavSummaryPattern("Vacances"),
+			}, tt.opts...)
+			cal := New(loc, opts...)
+			got, holiday, err := cal.GetHolidayNameFromCaldav(day)
+			if err != nil {
+				t.Errorf("GetHolidayNameFromCaldav() error = %v", err)
+				return
+			}
+			if !holiday {
+				t.Errorf("GetHolidayNameFromCaldav() %v should be a holiday", day)
+			}
+			if got != tt.want {
+				t.Errorf("GetHolidayNameFromCaldav() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// RangeCaldav returns the events overlapping the query range, like a server comparing date-only values in UTC
+type RangeCaldav struct {
+	events     []*components.Event
+	start, end time.Time
+}
+
+func (m *RangeCaldav) QueryEvents(_ string, query *entities.CalendarQuery) ([]*components.Event, error) {
+	var err error
+	if m.start, err = queryTime(query.Prop.CalendarData.ExpandRecurrenceSet.StartTime); err != nil {
+		return nil, err
+	}
+	if m.end, err = queryTime(query.Prop.CalendarData.ExpandRecurrenceSet.EndTime); err != nil {
+		return nil, err
+	}
+	var events []*components.Event
+	for _, evt := range m.events {
+		if evt.DateStart.NativeTime().Before(m.end) && evt.DateEnd.NativeTime().After(m.start) {
+			events = append(events, evt)
+		}
+	}
+	return events, nil
+}
+
+func queryTime(value xml.MarshalerAttr) (time.Time, error) {
+	attr, err := value.MarshalXMLAttr(xml.Name{})
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse("20060102T150405Z", attr.Value)
+}
+
+func TestCalendar_GetHolidayNameFromCaldav_AllDayEvent(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	// All-day event on 2022-04-16, date-only values are decoded as UTC midnight
+	cdav := &RangeCaldav{
+		events: []*components.Event{
+			{
+				UID:       "1",
+				DateStart: values.NewDateTime(time.Date(2022, time.April, 16, 0, 0, 0, 0, time.UTC)),
+				DateEnd:   values.NewDateTime(time.Date(2022, time.April, 17, 0, 0, 0, 0, time.UTC)),
+				Summary:   "Holidays",
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		opts      []Option
+		day       time.Time
+		want      bool
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{
+			name:      "Event day",
+			day:       time.Date(2022, time.April, 16, 0, 0, 0, 0, loc),
+			want:      true,
+			wantStart: time.Date(2022, time.April, 15, 22, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2022, time.April, 16, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "Event day in the afternoon",
+			day:       time.Date(2022, time.April, 16, 23, 30, 0, 0, loc),
+			want:      true,
+			wantStart: time.Date(2022, time.April, 15, 22, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2022, time.April, 16, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "Day after event",
+			day:       time.Date(2022, time.April, 17, 0, 0, 0, 0, loc),
+			want:      false,
+			wantStart: time.Date(2022, time.April, 16, 22, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2022, time.April, 17, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "Day before event with margin",
+			opts:      []Option{WithCaldavWindowMargin(12 * time.Hour)},
+			day:       time.Date(2022, time.April, 15, 0, 0, 0, 0, loc),
+			want:      false,
+			wantStart: time.Date(2022, time.April, 14, 10, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2022, time.April, 16, 10, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(loc, append([]Option{WithCaldav(cdav), WithCaldavSummaryPattern("Holidays")}, tt.opts...)...)
+			got, err := c.IsHolidaysFromCaldav(tt.day)
+			if err != nil {
+				t.Errorf("IsHolidaysFromCaldav() unexpected error: %v", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("IsHolidaysFromCaldav(%v) got = %v, want %v", tt.day, got, tt.want)
+			}
+			if !cdav.start.Equal(tt.wantStart) || !cdav.end.Equal(tt.wantEnd) {
+				t.Errorf("bad query window, expected:%v - %v ; actual:%v - %v", tt.wantStart, tt.wantEnd, cdav.start, cdav.end)
+			}
+		})
+	}
+}
+
+func TestCalendar_AllDayEventExclusiveEnd(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	// Monday to Friday off, DTEND is the Saturday after the last day off
+	raw := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\nBEGIN:VEVENT\r\nUID:week\r\nDTSTAMP:20240101T000000Z\r\n" +
+		"DTSTART;VALUE=DATE:20240415\r\nDTEND;VALUE=DATE:20240420\r\nSUMMARY:Holidays\r\nEND:VEVENT\r\nEND:VCALENDAR"
+	var parsed components.Calendar
+	if err := icalendar.Unmarshal(raw, &parsed); err != nil {
+		t.Fatalf("unable to parse event: %v", err)
+	}
+	cdav := &MockCaldav{events: parsed.Events}
+
+	tests := []struct {
+		name string
+		day  time.Time
+		want bool
+	}{
+		{name: "First day off", day: time.Date(2024, time.April, 15, 0, 0, 0, 0, loc), want: true},
+		{name: "Last day off", day: time.Date(2024, time.April, 19, 0, 0, 0, 0, loc), want: true},
+		{name: "Last day off in the evening", day: time.Date(2024, time.April, 19, 23, 0, 0, 0, loc), want: true},
+		{name: "Exclusive end", day: time.Date(2024, time.April, 20, 0, 0, 0, 0, loc), want: false},
+		{name: "Day before", day: time.Date(2024, time.April, 14, 0, 0, 0, 0, loc), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(loc, WithCaldav(cdav), WithCaldavSummaryPattern("Holidays"))
+			got, err := c.IsHolidaysFromCaldav(tt.day)
+			if err != nil {
+				t.Fatalf("IsHolidaysFromCaldav() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("IsHolidaysFromCaldav(%v) got = %v, want %v", tt.day, got, tt.want)
+			}
+			days, err := c.CaldavHolidayDays(tt.day.AddDate(0, 0, -7), tt.day.AddDate(0, 0, 7))
+			if err != nil {
+				t.Fatalf("CaldavHolidayDays() unexpected error: %v", err)
+			}
+			if days[c.midnight(tt.day)] != tt.want {
+				t.Errorf("CaldavHolidayDays() of %v got = %v, want %v", tt.day, !tt.want, tt.want)
+			}
+			if len(days) != 5 {
+				t.Errorf("bad caldav holidays count, expected:5 ; actual:%v", len(days))
+			}
+		})
+	}
+}
+
+func TestCalendar_WithCaldavAllDayOnly(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	cdav := &MockCaldav{
+		events: []*components.Event{
+			{
+				UID:       "1",
+				DateStart: values.NewDateTime(time.Date(2022, time.April, 13, 14, 0, 0, 0, loc)),
+				DateEnd:   values.NewDateTime(time.Date(2022, time.April, 13, 16, 0, 0, 0, loc)),
+				Summary:   "Holidays planning",
+			},
+			{
+				UID:       "2",
+				DateStart: values.NewDateTime(time.Date(2022, time.April, 20, 0, 0, 0, 0, time.UTC)),
+				DateEnd:   values.NewDateTime(time.Date(2022, time.April, 21, 0, 0, 0, 0, time.UTC)),
+				Summary:   "Holidays",
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		allDayOnly bool
+		day        time.Time
+		want       bool
+	}{
+		{name: "Timed event", allDayOnly: false, day: time.Date(2022, time.April, 13, 0, 0, 0, 0, loc), want: true},
+		{name: "Timed event all-day only", allDayOnly: true, day: time.Date(2022, time.April, 13, 0, 0, 0, 0, loc), want: false},
+		{name: "All-day event", allDayOnly: false, day: time.Date(2022, time.April, 20, 0, 0, 0, 0, loc), want: true},
+		{name: "All-day event all-day only", allDayOnly: true, day: time.Date(2022, time.April, 20, 0, 0, 0, 0, loc), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(loc, WithCaldav(cdav), WithCaldavSummaryPattern("Holidays"), WithCaldavAllDayOnly(tt.allDayOnly))
+			got, err := c.IsHolidaysFromCaldav(tt.day)
+			if err != nil {
+				t.Errorf("IsHolidaysFromCaldav() unexpected error: %v", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("IsHolidaysFromCaldav(%v) got = %v, want %v", tt.day, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalendar_WithCaldavMaxEvents(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	day := time.Date(2022, time.April, 13, 0, 0, 0, 0, loc)
+	cdav := &MockCaldav{}
+	for i := 0; i < 5; i++ {
+		cdav.events = append(cdav.events, &components.Event{
+			UID:       fmt.Sprint(i),
+			DateStart: values.NewDateTime(day.Add(time.Duration(i) * time.Hour)),
+			DateEnd:   values.NewDateTime(day.Add(time.Duration(i+1) * time.Hour)),
+			Summary:   "Meeting",
+		})
+	}
+	cdav.events = append(cdav.events, &components.Event{
+		UID:       "holidays",
+		DateStart: values.NewDateTime(day),
+		DateEnd:   values.NewDateTime(day.AddDate(0, 0, 1)),
+		Summary:   "Holidays",
+	})
+
+	tests := []struct {
+		name         string
+		maxEvents    int
+		want         bool
+		wantWarnings int
+	}{
+		{name: "No limit", maxEvents: 0, want: true, wantWarnings: 0},
+		{name: "Under limit", maxEvents: 6, want: true, wantWarnings: 0},
+		{name: "Limit exceeded", maxEvents: 5, want: false, wantWarnings: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := recordingLogger{}
+			c := New(loc, WithCaldav(cdav), WithCaldavSummaryPattern("Holidays"), WithCaldavMaxEvents(tt.maxEvents), WithLogger(&logger))
+			got, err := c.IsHolidaysFromCaldav(day)
+			if err != nil {
+				t.Errorf("IsHolidaysFromCaldav() unexpected error: %v", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("IsHolidaysFromCaldav() got = %v, want %v", got, tt.want)
+			}
+			if len(logger.warnings) != tt.wantWarnings {
+				t.Errorf("bad warnings, expected:%v ; actual:%v", tt.wantWarnings, logger.warnings)
+			}
+		})
+	}
+}
+
+// FlakyCaldav fails the first failures queries with err, then returns the events of the wrapped MockCaldav
+type FlakyCaldav struct {
+	MockCaldav
+	failures int
+	err      error
+	queries  int
+}
+
+func (m *FlakyCaldav) QueryEvents(path string, query *entities.CalendarQuery) ([]*components.Event, error) {
+	m.queries++
+	if m.queries <= m.failures {
+		return nil, m.err
+	}
+	return m.MockCaldav.QueryEvents(path, query)
+}
+
+func TestCalendar_WithCaldavQueryAttempts(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	day := time.Date(2022, time.April, 13, 0, 0, 0, 0, loc)
+	events := []*components.Event{
+		{
+			UID:       "1",
+			DateStart: values.NewDateTime(day),
+			DateEnd:   values.NewDateTime(day.AddDate(0, 0, 1)),
+			Summary:   "Holidays",
+		},
+	}
+
+	tests := []struct {
+		name        string
+		opts        []Option
+		err         error
+		want        bool
+		wantErr     bool
+		wantQueries int
+	}{
+		{name: "Single attempt by default", err: fmt.Errorf("connection reset"), wantErr: true, wantQueries: 1},
+		{name: "Retry once", opts: []Option{WithCaldavQueryAttempts(2)}, err: fmt.Errorf("connection reset"), want: true, wantQueries: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cdav := &FlakyCaldav{MockCaldav: MockCaldav{events: events}, failures: 1, err: tt.err}
+			c := New(loc, append([]Option{WithCaldav(cdav), WithCaldavSummaryPattern("Holidays")}, tt.opts...)...)
+			got, err := c.IsHolidaysFromCaldav(day)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("IsHolidaysFromCaldav() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("bad holiday status, expected:%v ; actual:%v", tt.want, got)
+			}
+			if cdav.queries != tt.wantQueries {
+				t.Errorf("bad queries count, expected:%v ; actual:%v", tt.wantQueries, cdav.queries)
+			}
+		})
+	}
+}
+
+func TestCalendar_WithCaldavQueryAttempts_RateLimit(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	reports := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions {
+			w.Header().Set("DAV", "1, 2, calendar-access")
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		reports++
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	cdav, err := NewCaldav(server.URL, "/calendars/")
+	if err != nil {
+		t.Fatalf("unable to init caldav: %v", err)
+	}
+	logger := &recordingLogger{}
+	c := New(loc, WithCaldav(cdav), WithCaldavSummaryPattern("Holidays"), WithCaldavQueryAttempts(3), WithLogger(logger))
+
+	day := time.Date(2022, time.April, 13, 0, 0, 0, 0, loc)
+	if _, err := c.IsHolidaysFromCaldav(day); err == nil {
+		t.Error("rate limited query should fail")
+	}
+	if reports != 1 {
+		t.Errorf("rate limited query should not be retried, expected 1 query ; actual:%v", reports)
+	}
+	if _, err := c.IsHolidaysFromCaldav(day.AddDate(0, 0, 1)); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("queries should be paused, got error %v", err)
+	}
+	if reports != 1 {
+		t.Errorf("server should not be queried while paused: %d queries", reports)
+	}
+	for _, w := range logger.warnings {
+		if strings.Contains(w, "retry") {
+			t.Errorf("rate limited query should not be retried: %v", w)
+		}
+	}
+}
+
+func TestCalendar_WithCaldavSummaryPatterns(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	event := func(uid string, day int, summary string) *components.Event {
+		start := time.Date(2024, time.May, day, 0, 0, 0, 0, loc)
+		return &components.Event{UID: uid, DateStart: values.NewDateTime(start), DateEnd: values.NewDateTime(start.AddDate(0, 0, 1)), Summary: summary}
+	}
+	cdav := &MockCaldav{
+		events: []*components.Event{
+			event("1", 13, "Congés"),
+			event("2", 14, "RTT"),
+			event("3", 15, "Télétravail"),
+			event("4", 16, "Dentiste"),
+			event("5", 17, "rtt"),
+		},
+	}
+
+	tests := []struct {
+		name string
+		opts []Option
+		want map[int]bool
+	}{
+		{
+			name: "Any pattern",
+			opts: []Option{WithCaldavSummaryPatterns([]string{"Congés", "RTT", "Télétravail"})},
+			want: map[int]bool{13: true, 14: true, 15: true},
+		},
+		{
+			name: "Case insensitive",
+			opts: []Option{WithCaldavSummaryPatterns([]string{"Congés", "RTT"}), WithCaldavCaseInsensitive(true)},
+			want: map[int]bool{13: true, 14: true, 17: true},
+		},
+		{
+			name: "Replaced by single pattern",
+			opts: []Option{WithCaldavSummaryPatterns([]string{"Congés", "RTT"}), WithCaldavSummaryPattern("Dentiste")},
+			want: map[int]bool{16: true},
+		},
+		{
+			name: "Empty patterns ignored",
+			opts: []Option{WithCaldavSummaryPatterns([]string{"", "Congés"})},
+			want: map[int]bool{13: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(loc, append([]Option{WithCaldav(cdav)}, tt.opts...)...)
+			for day := 13; day <= 17; day++ {
+				got, err := c.IsHolidaysFromCaldav(time.Date(2024, time.May, day, 0, 0, 0, 0, loc))
+				if err != nil {
+					t.Fatalf("IsHolidaysFromCaldav() unexpected error: %v", err)
+				}
+				if got != tt.want[day] {
+					t.Errorf("IsHolidaysFromCaldav(2024-05-%d) got = %v, want %v", day, got, tt.want[day])
+				}
+			}
+		})
+	}
+}
+
+func TestCalendar_WithCaldavNormalize(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	event := func(uid string, day int, summary string) *components.Event {
+		start := time.Date(2024, time.May, day, 0, 0, 0, 0, loc)
+		return &components.Event{UID: uid, DateStart: values.NewDateTime(start), DateEnd: values.NewDateTime(start.AddDate(0, 0, 1)), Summary: summary}
+	}
+	cdav := &MockCaldav{
+		events: []*components.Event{
+			event("1", 13, "Congés  "),
+			event("2", 14, "Conges"),
+			event("3", 15, "congés d'été"),
+			event("4", 16, "Dentiste"),
+		},
+	}
+
+	tests := []struct {
+		name string
+		opts []Option
+		want map[int]bool
+	}{
+		{
+			name: "Accents removed",
+			opts: []Option{WithCaldavSummaryPattern("Conges"), WithCaldavNormalize(true)},
+			want: map[int]bool{13: true, 14: true},
+		},
+		{
+			name: "Pattern normalized",
+			opts: []Option{WithCaldavSummaryPattern(" Congés "), WithCaldavNormalize(true)},
+			want: map[int]bool{13: true, 14: true},
+		},
+		{
+			name: "Case insensitive",
+			opts: []Option{WithCaldavSummaryPattern("CONGES"), WithCaldavNormalize(true), WithCaldavCaseInsensitive(true)},
+			want: map[int]bool{13: true, 14: true, 15: true},
+		},
+		{
+			name: "Blank pattern ignored",
+			opts: []Option{WithCaldavSummaryPattern("  "), WithCaldavNormalize(true)},
+			want: map[int]bool{},
+		},
+		{
+			name: "Disabled by default",
+			opts: []Option{WithCaldavSummaryPattern("Conges")},
+			want: map[int]bool{14: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(loc, append([]Option{WithCaldav(cdav)}, tt.opts...)...)
+			for day := 13; day <= 16; day++ {
+				got, err := c.IsHolidaysFromCaldav(time.Date(2024, time.May, day, 0, 0, 0, 0, loc))
+				if err != nil {
+					t.Fatalf("IsHolidaysFromCaldav() unexpected error: %v", err)
+				}
+				if got != tt.want[day] {
+					t.Errorf("IsHolidaysFromCaldav(2024-05-%d) got = %v, want %v", day, got, tt.want[day])
+				}
+			}
+		})
+	}
+}
+
+func TestCalendar_CaldavEventAt(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	cdav := &MockCaldav{
+		events: []*components.Event{
+			{UID: "dentist", DateStart: values.NewDateTime(time.Date(2024, time.July, 1, 0, 0, 0, 0, loc)), DateEnd: values.NewDateTime(time.Date(2024, time.July, 2, 0, 0, 0, 0, loc)), Summary: "Dentist"},
+			{UID: "summer", DateStart: values.NewDateTime(time.Date(2024, time.July, 1, 0, 0, 0, 0, loc)), DateEnd: values.NewDateTime(time.Date(2024, time.July, 6, 0, 0, 0, 0, loc)), Summary: "Holidays: summer"},
+		},
+	}
+	want := &CaldavEvent{UID: "summer", Summary: "Holidays: summer"}
+	day := time.Date(2024, time.July, 3, 0, 0, 0, 0, loc)
+
+	tests := []struct {
+		name string
+		cal  func(c *Calendar) *Calendar
+	}{
+		{name: "Single day query", cal: func(c *Calendar) *Calendar { return c }},
+		{name: "Range query", cal: func(c *Calendar) *Calendar { return c.ForRange(day.AddDate(0, 0, -2), day.AddDate(0, 0, 2)) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.cal(New(loc, WithCaldav(cdav), WithCaldavSummaryPattern("Holidays"), WithCaldavNameExtractor(func(summary string) string {
+				return strings.TrimPrefix(summary, "Holidays: ")
+			})))
+			got, err := c.CaldavEventAt(day)
+			if err != nil {
+				t.Fatalf("CaldavEventAt() unexpected error: %v", err)
+			}
+			if got == nil || *got != *want {
+				t.Errorf("bad event, expected:%v ; actual:%v", want, got)
+			}
+			if name, _, _ := c.GetHolidayNameFromCaldav(day); name != "summer" {
+				t.Errorf("bad holiday name, expected:summer ; actual:%v", name)
+			}
+			if got, err := c.CaldavEventAt(day.AddDate(0, 0, 4)); err != nil || got != nil {
+				t.Errorf("day without event should have no event: %v, %v", got, err)
+			}
+		})
+	}
+}
+
+func TestCalendar_WithCaldavCaseInsensitive(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	day := time.Date(2022, time.April, 13, 0, 0, 0, 0, loc)
+	cdav := &MockCaldav{
+		events: []*components.Event{
+			{
+				UID:       "1",
+				DateStart: values.NewDateTime(day),
+				DateEnd:   values.NewDateTime(day.AddDate(0, 0, 1)),
+				Summary:   "CONGÉS",
+			},
+		},
+	}
+
+	tests := []struct {
+		name string
+		opts []Option
+		want bool
+	}{
+		{name: "Case sensitive by default", opts: nil, want: false},
+		{name: "Case insensitive", opts: []Option{WithCaldavCaseInsensitive(true)}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(loc, append([]Option{WithCaldav(cdav), WithCaldavSummaryPattern("Congés")}, tt.opts...)...)
+			got, err := c.IsHolidaysFromCaldav(day)
+			if err != nil {
+				t.Errorf("IsHolidaysFromCaldav() unexpected error: %v", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("IsHolidaysFromCaldav() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalendar_HolidayName(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	tests := []struct {
+		name        string
+		cdav        *MockCaldav
+		date        time.Time
+		wantName    string
+		wantHoliday bool
+	}{
+		{
+			name:        "National holiday",
+			cdav:        &MockCaldav{},
+			date:        time.Date(2022, time.July, 14, 10, 0, 0, 0, loc),
+			wantName:    "Fête nationale",
+			wantHoliday: true,
+		},
+		{
+			name: "Caldav holiday",
+			cdav: &MockCaldav{
+				events: []*components.Event{
+					{
+						UID:       "1",
+						DateStart: values.NewDateTime(time.Date(2022, time.April, 13, 0, 0, 0, 0, loc)),
+						DateEnd:   values.NewDateTime(time.Date(2022, time.April, 14, 0, 0, 0, 0, loc)),
+						Summary:   "Holidays",
+					},
+				},
+			},
+			date:        time.Date(2022, time.April, 13, 10, 0, 0, 0, loc),
+			wantName:    "Holidays",
+			wantHoliday: true,
+		},
+		{
+			name:        "Ordinary weekday",
+			cdav:        &MockCaldav{},
+			date:        time.Date(2022, time.April, 12, 10, 0, 0, 0, loc),
+			wantName:    "",
+			wantHoliday: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(loc, WithCaldav(tt.cdav), WithCaldavSummaryPattern("Holidays"))
+			got, holiday := c.HolidayName(tt.date)
+			if got != tt.wantName || holiday != tt.wantHoliday {
+				t.Errorf("HolidayName() got = (%v, %v), want (%v, %v)", got, holiday, tt.wantName, tt.wantHoliday)
+			}
+		})
+	}
+}
+
+func TestCalendar_HolidayAt_Source(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	cdav := &MockCaldav{
+		events: []*components.Event{
+			{
+				UID:       "1",
+				DateStart: values.NewDateTime(time.Date(2022, time.July, 13, 0, 0, 0, 0, loc)),
+				DateEnd:   values.NewDateTime(time.Date(2022, time.July, 15, 0, 0, 0, 0, loc)),
+				Summary:   "Holidays",
+			},
+		},
+	}
+	tests := []struct {
+		name       string
+		date       time.Time
+		wantSource string
+	}{
+		{
+			name:       "National holiday also in caldav",
+			date:       time.Date(2022, time.July, 14, 10, 0, 0, 0, loc),
+			wantSource: SourceNational,
+		},
+		{
+			name:       "Caldav holiday",
+			date:       time.Date(2022, time.July, 13, 10, 0, 0, 0, loc),
+			wantSource: SourceCaldav,
+		},
+		{
+			name:       "Ordinary weekday",
+			date:       time.Date(2022, time.July, 12, 10, 0, 0, 0, loc),
+			wantSource: "",
+		},
+	}
+	c := New(loc, WithCaldav(cdav), WithCaldavSummaryPattern("Holidays"))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, holiday := c.HolidayAt(tt.date)
+			if h.Source != tt.wantSource {
+				t.Errorf("bad source, expected:%v ; actual:%v", tt.wantSource, h.Source)
+			}
+			if holiday != (tt.wantSource != "") {
+				t.Errorf("bad holiday status for source %q: %v", tt.wantSource, holiday)
+			}
+		})
+	}
+}
+
+func TestCalendar_WithWeekendObservance(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	// 14 July 2024 is a Sunday, 1 May 2027 a Saturday
+	sunday := time.Date(2024, time.July, 14, 0, 0, 0, 0, loc)
+	monday := time.Date(2024, time.July, 15, 0, 0, 0, 0, loc)
+	saturday := time.Date(2027, time.May, 1, 0, 0, 0, 0, loc)
+	friday := time.Date(2027, time.April, 30, 0, 0, 0, 0, loc)
+
+	c := New(loc)
+	if c.IsHoliday(monday) {
+		t.Errorf("%v should not be observed without weekend observance", monday)
+	}
+
+	c = New(loc, WithWeekendObservance(true))
+	tests := []struct {
+		name         string
+		date         time.Time
+		wantHoliday  bool
+		wantObserved bool
+	}{
+		{name: "Sunday holiday kept", date: sunday, wantHoliday: true},
+		{name: "Sunday holiday observed on Monday", date: monday, wantHoliday: true, wantObserved: true},
+		{name: "Saturday holiday observed on Friday", date: friday, wantHoliday: true, wantObserved: true},
+		{name: "Saturday holiday kept", date: saturday, wantHoliday: true},
+		{name: "Weekday holiday not moved", date: time.Date(2024, time.May, 9, 0, 0, 0, 0, loc), wantHoliday: true},
+		{name: "Day after weekday holiday", date: time.Date(2024, time.May, 2, 0, 0, 0, 0, loc)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, holiday := c.HolidayAt(tt.date)
+			if holiday != tt.wantHoliday {
+				t.Errorf("bad holiday status, expected:%v ; actual:%v", tt.wantHoliday, holiday)
+			}
+			if h.Observed != tt.wantObserved {
+				t.Errorf("bad observed status, expected:%v ; actual:%v", tt.wantObserved, h.Observed)
+			}
+		})
+	}
+	if h, _ := c.HolidayAt(monday); h.Name != "Fête nationale" || h.Key != "national_day" {
+		t.Errorf("observed holiday should keep the name of the holiday: %+v", h)
+	}
+	if c.IsWorkingDay(monday) {
+		t.Errorf("observed holiday %v should not be a working day", monday)
+	}
+}
+
+func TestCalendar_WithWeekendObservance_YearBoundary(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	// 1 January 2022 is a Saturday, observed on Friday 31 December 2021
+	observed := time.Date(2021, time.December, 31, 0, 0, 0, 0, loc)
+	c := New(loc, WithWeekendObservance(true))
+
+	for _, h := range c.GetHolidaysNamed(2022) {
+		if h.Date.Year() != 2022 {
+			t.Errorf("holidays of 2022 should only be in 2022: %+v", h)
+		}
+	}
+	found := false
+	for _, h := range c.GetHolidaysNamed(2021) {
+		if h.Date.Equal(observed) {
+			found = true
+			if !h.Observed || h.Key != "new_year" {
+				t.Errorf("bad observed holiday: %+v", h)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("%v should be a holiday of 2021", observed)
+	}
+	if !c.IsHoliday(observed) {
+		t.Errorf("%v should be a holiday", observed)
+	}
+	if c.IsWorkingDay(observed) {
+		t.Errorf("%v should not be a working day", observed)
+	}
+	// Saturday 25 December 2021 is observed on Friday 24 December
+	if !c.IsHoliday(time.Date(2021, time.December, 24, 0, 0, 0, 0, loc)) {
+		t.Errorf("24 December 2021 should be a holiday")
+	}
+}
+
+func TestCalendar_WithWeekendObservance_CustomWeekend(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	tests := []struct {
+		name        string
+		opts        []Option
+		date        time.Time
+		wantHoliday bool
+	}{
+		{
+			// Friday 14 July 2023 is a weekend day, Saturday too
+			name:        "Friday weekend observed on Thursday",
+			opts:        []Option{WithWeekend(time.Friday, time.Saturday)},
+			date:        time.Date(2023, time.July, 13, 0, 0, 0, 0, loc),
+			wantHoliday: true,
+		},
+		{
+			name: "Saturday working, Friday not observed",
+			opts: []Option{WithSaturdayWorking(true)},
+			date: time.Date(2027, time.April, 30, 0, 0, 0, 0, loc),
+		},
+		{
+			// Sunday 14 July 2024, Saturday 13 and Monday 15 are as near
+			name:        "Saturday working, Sunday observed on Monday",
+			opts:        []Option{WithSaturdayWorking(true)},
+			date:        time.Date(2024, time.July, 15, 0, 0, 0, 0, loc),
+			wantHoliday: true,
+		},
+		{
+			name: "Saturday working, Sunday not observed on Saturday",
+			opts: []Option{WithSaturdayWorking(true)},
+			date: time.Date(2024, time.July, 13, 0, 0, 0, 0, loc),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(loc, append([]Option{WithWeekendObservance(true)}, tt.opts...)...)
+			if got := c.IsHoliday(tt.date); got != tt.wantHoliday {
+				t.Errorf("bad holiday status of %v, expected:%v ; actual:%v", tt.date, tt.wantHoliday, got)
+			}
+		})
+	}
+}
+
+func TestCalendar_WorkingDays(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	c := New(loc)
+
+	// 8 May and Ascension on Wednesday and Thursday, then a weekend
+	start := time.Date(2024, time.May, 7, 15, 0, 0, 0, loc)
+	end := time.Date(2024, time.May, 14, 8, 0, 0, 0, time.UTC)
+	want := []time.Time{
+		time.Date(2024, time.May, 7, 0, 0, 0, 0, loc),
+		time.Date(2024, time.May, 10, 0, 0, 0, 0, loc),
+		time.Date(2024, time.May, 13, 0, 0, 0, 0, loc),
+		time.Date(2024, time.May, 14, 0, 0, 0, 0, loc),
+	}
+
+	days := c.WorkingDays(start, end)
+	if len(days) != len(want) {
+		t.Fatalf("bad working days, expected:%v ; actual:%v", want, days)
+	}
+	for i, d := range days {
+		if !d.Equal(want[i]) || d.Location() != loc {
+			t.Errorf("bad working day, expected:%v ; actual:%v", want[i], d)
+		}
+	}
+	if count := c.CountWorkingDays(start, end); count != len(want) {
+		t.Errorf("bad working days count, expected:%v ; actual:%v", len(want), count)
+	}
+	if days := c.WorkingDays(end, start); len(days) != 0 {
+		t.Errorf("no working day expected when end is before start: %v", days)
+	}
+}
+
+func TestCalendar_WorkingDaysStatus(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	cdav := &CountingCaldav{
+		MockCaldav: MockCaldav{
+			events: []*components.Event{
+				{
+					UID:       "1",
+					DateStart: values.NewDateTime(time.Date(2024, time.April, 15, 0, 0, 0, 0, loc)),
+					DateEnd:   values.NewDateTime(time.Date(2024, time.April, 20, 0, 0, 0, 0, loc)),
+					Summary:   "Holidays",
+				},
+			},
+		},
+	}
+	c := New(loc, WithCaldav(cdav), WithCaldavSummaryPattern("Holidays"))
+
+	// Unsorted: a CalDAV holiday, Easter Monday, a weekend day, working days, a duplicate
+	dates := []time.Time{
+		time.Date(2024, time.April, 17, 0, 0, 0, 0, loc),
+		time.Date(2024, time.April, 1, 0, 0, 0, 0, loc),
+		time.Date(2024, time.April, 6, 0, 0, 0, 0, loc),
+		time.Date(2024, time.April, 9, 0, 0, 0, 0, loc),
+		time.Date(2024, time.April, 22, 0, 0, 0, 0, loc),
+		time.Date(2024, time.April, 17, 0, 0, 0, 0, loc),
+	}
+	got := c.WorkingDaysStatus(dates)
+	if cdav.queries != 1 {
+		t.Errorf("bad caldav queries count, expected:1 ; actual:%v", cdav.queries)
+	}
+	if len(got) != len(dates) {
+		t.Fatalf("bad status count, expected:%v ; actual:%v", len(dates), len(got))
+	}
+	for i, d := range dates {
+		if want := c.IsWorkingDay(d); got[i] != want {
+			t.Errorf("bad status of %v, expected:%v ; actual:%v", d, want, got[i])
+		}
+	}
+	if want := []bool{false, false, false, true, true, false}; !reflect.DeepEqual(got, want) {
+		t.Errorf("bad status, expected:%v ; actual:%v", want, got)
+	}
+
+	if got := c.WorkingDaysStatus(nil); len(got) != 0 {
+		t.Errorf("no dates should have no status: %v", got)
+	}
+}
+
+func TestCalendar_GetHolidayNameFromCaldav_EventTimezone(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("unable to load time location: %v", err)
+	}
+	tests := []struct {
+		name  string
+		start time.Time
+		end   time.Time
+		want  map[int]bool
+	}{
+		{
+			name:  "All-day event in its own timezone",
+			start: time.Date(2024, time.April, 13, 0, 0, 0, 0, newYork),
+			end:   time.Date(2024, time.April, 14, 0, 0, 0, 0, newYork),
+			want:  map[int]bool{12: false, 13: true, 14: false},
+		},
+		{
+			name:  "Evening event in New York is the next day in Paris",
+			start: time.Date(2024, time.April, 12, 20, 0, 0, 0, newYork),
+			end:   time.Date(2024, time.April, 12, 22, 0, 0, 0, newYork),
+			want:  map[int]bool{12: false, 13: true, 14: false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(loc,
+				WithCaldav(&MockCaldav{
+					events: []*components.Event{
+						{
+							UID:       "1",
+							DateStart: values.NewDateTime(tt.start),
+							DateEnd:   values.NewDateTime(tt.end),
+							Summary:   "Holidays",
+						},
+					},
+				}),
+				WithCaldavSummaryPattern("Holidays"),
+			)
+			for day, want := range tt.want {
+				date := time.Date(2024, time.April, day, 0, 0, 0, 0, loc)
+				_, holiday, err := c.GetHolidayNameFromCaldav(date)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if holiday != want {
+					t.Errorf("bad holiday status of %v, expected:%v ; actual:%v", date, want, holiday)
+				}
+			}
+		})
+	}
+}
+
+func TestCalendar_EmptySummaryPattern(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	day := time.Date(2022, time.April, 13, 0, 0, 0, 0, loc)
+	cdav := &MockCaldav{
+		events: []*components.Event{
+			{
+				UID:       "1",
+				DateStart: values.NewDateTime(day),
+				DateEnd:   values.NewDateTime(day.AddDate(0, 0, 1)),
+				Summary:   "Dentist",
+			},
+			{
+				UID:       "2",
+				DateStart: values.NewDateTime(day),
+				DateEnd:   values.NewDateTime(day.AddDate(0, 0, 1)),
+				Summary:   "",
+			},
+		},
+	}
+
+	for name, opts := range map[string][]Option{
+		"Default pattern":        {WithCaldav(cdav)},
+		"Empty pattern":          {WithCaldav(cdav), WithCaldavSummaryPattern("")},
+		"Empty case insensitive": {WithCaldav(cdav), WithCaldavSummaryPattern(""), WithCaldavCaseInsensitive(true)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			c := New(loc, opts...)
+			got, err := c.IsHolidaysFromCaldav(day)
+			if err != nil {
+				t.Fatalf("IsHolidaysFromCaldav() unexpected error: %v", err)
+			}
+			if got || c.IsHoliday(day) {
+				t.Errorf("no event should match an empty summary pattern")
+			}
+		})
+	}
+}
+
+func TestCalendar_MalformedCaldavEvents(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	day := time.Date(2022, time.April, 13, 0, 0, 0, 0, loc)
+	next := day.AddDate(0, 0, 1)
+	cdav := &MockCaldav{
+		events: []*components.Event{
+			nil,
+			{UID: "no-start", DateEnd: values.NewDateTime(next), Summary: "Holidays"},
+			{UID: "no-end", DateStart: values.NewDateTime(day), Summary: "Holidays"},
+			{UID: "valid", DateStart: values.NewDateTime(next), DateEnd: values.NewDateTime(next.AddDate(0, 0, 1)), Summary: "Holidays"},
+		},
+	}
+	logger := recordingLogger{}
+	c := New(loc, WithCaldav(cdav), WithCaldavSummaryPattern("Holidays"), WithLogger(&logger))
+
+	got, err := c.IsHolidaysFromCaldav(day)
+	if err != nil {
+		t.Fatalf("IsHolidaysFromCaldav() unexpected error: %v", err)
+	}
+	if got {
+		t.Errorf("malformed events should be ignored for %v", day)
+	}
+	if len(logger.debugs) != 2 {
+		t.Errorf("malformed events should be logged, got %v", logger.debugs)
+	}
+	if got, err := c.IsHolidaysFromCaldav(next); err != nil || !got {
+		t.Errorf("valid event should still match %v: %v, %v", next, got, err)
+	}
+	if holidays := c.HolidaysOfYear(2022); len(holidays) != 12 {
+		t.Errorf("bad holidays with malformed events, expected 11 national and 1 caldav: %v", holidays)
+	}
+}
+
+func BenchmarkCalendar_GetEasterDay(b *testing.B) {
+	c := New(time.UTC)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		c.GetEasterDay(2000 + i%100)
+	}
+}
+
+func BenchmarkCalendar_GetHolidaysSet(b *testing.B) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		b.Fatalf("unable to load time location: %v", err)
+	}
+	c := New(loc)
+	day := time.Date(2024, time.May, 8, 0, 0, 0, 0, loc)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = c.GetHolidaysSet(day.Year())[day]
+	}
+}
+
+func BenchmarkCalendar_IsHoliday(b *testing.B) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		b.Fatalf("unable to load time location: %v", err)
+	}
+	c := New(loc)
+	day := time.Date(2024, time.May, 8, 10, 0, 0, 0, loc)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		c.IsHoliday(day)
+	}
+}
+
+func TestCalendar_WithNationalHolidays(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	newYear := time.Date(2024, time.January, 1, 0, 0, 0, 0, loc)
+	goodFriday := time.Date(2024, time.March, 29, 0, 0, 0, 0, loc)
+	caldavDay := time.Date(2024, time.April, 15, 0, 0, 0, 0, loc)
+	customDay := time.Date(2024, time.March, 17, 0, 0, 0, 0, loc)
+	cdav := &MockCaldav{
+		events: []*components.Event{
+			{
+				UID:       "1",
+				DateStart: values.NewDateTime(caldavDay),
+				DateEnd:   values.NewDateTime(caldavDay.AddDate(0, 0, 1)),
+				Summary:   "Holidays",
+			},
+		},
+	}
+
+	c := New(loc, WithNationalHolidays(false))
+	if c.IsHoliday(newYear) || !c.IsWorkingDay(newYear) {
+		t.Errorf("%v should be a working day without national holidays", newYear)
+	}
+
+	c = New(loc, WithNationalHolidays(false), WithRegion(RegionAlsaceMoselle), WithSaintStephen(),
+		WithCaldav(cdav), WithCaldavSummaryPattern("Holidays"),
+		WithCustomHolidays([]CustomHoliday{{Month: time.March, Day: 17, Name: "Anniversaire de la société"}}))
+	tests := []struct {
+		name string
+		day  time.Time
+		want bool
+	}{
+		{name: "National holiday", day: newYear, want: false},
+		{name: "Region holiday", day: goodFriday, want: false},
+		{name: "Option holiday", day: time.Date(2024, time.December, 26, 0, 0, 0, 0, loc), want: false},
+		{name: "CalDAV holiday", day: caldavDay, want: true},
+		{name: "Custom holiday", day: customDay, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.IsHoliday(tt.day); got != tt.want {
+				t.Errorf("bad holiday status of %v, expected:%v ; actual:%v", tt.day, tt.want, got)
+			}
+		})
+	}
+	if holidays := c.GetHolidaysNamed(2024); len(holidays) != 1 || !holidays[0].Date.Equal(customDay) {
+		t.Errorf("only custom holidays should be listed: %v", holidays)
+	}
+}
+
+func TestCalendar_CaldavHolidayDays(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	event := func(uid string, start, end time.Time, summary string) *components.Event {
+		return &components.Event{UID: uid, DateStart: values.NewDateTime(start), DateEnd: values.NewDateTime(end), Summary: summary}
+	}
+	cdav := &CountingCaldav{
+		MockCaldav: MockCaldav{
+			events: []*components.Event{
+				event("1", time.Date(2023, time.December, 31, 0, 0, 0, 0, loc), time.Date(2024, time.January, 2, 0, 0, 0, 0, loc), "Holidays"),
+				event("2", time.Date(2024, time.February, 14, 0, 0, 0, 0, loc), time.Date(2024, time.February, 15, 0, 0, 0, 0, loc), "Holidays"),
+				event("3", time.Date(2024, time.July, 1, 0, 0, 0, 0, loc), time.Date(2024, time.July, 4, 0, 0, 0, 0, loc), "Holidays"),
+				event("4", time.Date(2024, time.October, 10, 0, 0, 0, 0, loc), time.Date(2024, time.October, 11, 0, 0, 0, 0, loc), "Dentist"),
+			},
+		},
+	}
+	c := New(loc, WithCaldav(cdav), WithCaldavSummaryPattern("Holidays"), WithCaldavCacheTTL(time.Hour))
+
+	days, err := c.CaldavHolidayDays(time.Date(2024, time.January, 1, 0, 0, 0, 0, loc), time.Date(2024, time.December, 31, 0, 0, 0, 0, loc))
+	if err != nil {
+		t.Fatalf("CaldavHolidayDays() unexpected error: %v", err)
+	}
+	want := []time.Time{
+		time.Date(2024, time.January, 1, 0, 0, 0, 0, loc),
+		time.Date(2024, time.February, 14, 0, 0, 0, 0, loc),
+		time.Date(2024, time.July, 1, 0, 0, 0, 0, loc),
+		time.Date(2024, time.July, 2, 0, 0, 0, 0, loc),
+		time.Date(2024, time.July, 3, 0, 0, 0, 0, loc),
+	}
+	if len(days) != len(want) {
+		t.Errorf("bad days, expected:%v ; actual:%v", want, days)
+	}
+	for _, d := range want {
+		if !days[d] {
+			t.Errorf("%v should be a caldav holiday", d)
+		}
+	}
+	if cdav.queries != 1 {
+		t.Errorf("bad queries count, expected:1 ; actual:%v", cdav.queries)
+	}
+
+	// Days of the range are cached
+	for _, d := range []time.Time{time.Date(2024, time.July, 2, 0, 0, 0, 0, loc), time.Date(2024, time.March, 3, 0, 0, 0, 0, loc)} {
+		if _, err := c.IsHolidaysFromCaldav(d); err != nil {
+			t.Fatalf("IsHolidaysFromCaldav() unexpected error: %v", err)
+		}
+	}
+	if cdav.queries != 1 {
+		t.Errorf("days of the range should be cached, queries count:%v", cdav.queries)
+	}
+}
+
+func TestCalendar_NthWorkingDayOfMonth(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	c := New(loc)
+
+	// May 2024 has 19 working days, from Thursday 2 to Friday 31
+	tests := []struct {
+		name   string
+		n      int
+		want   time.Time
+		wantOk bool
+	}{
+		{name: "First", n: 1, want: time.Date(2024, time.May, 2, 0, 0, 0, 0, loc), wantOk: true},
+		{name: "Fifth, after a weekend", n: 5, want: time.Date(2024, time.May, 10, 0, 0, 0, 0, loc), wantOk: true},
+		{name: "Last", n: 19, want: time.Date(2024, time.May, 31, 0, 0, 0, 0, loc), wantOk: true},
+		{name: "Beyond last", n: 20},
+		{name: "Zero", n: 0},
+		{name: "Negative", n: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := c.NthWorkingDayOfMonth(2024, time.May, tt.n)
+			if ok != tt.wantOk {
+				t.Fatalf("bad status, expected:%v ; actual:%v", tt.wantOk, ok)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("bad working day, expected:%v ; actual:%v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCalendar_YearStats(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	// A week of CalDAV holidays, Monday 15 to Friday 19 April 2024
+	cdav := &CountingCaldav{
+		MockCaldav: MockCaldav{
+			events: []*components.Event{
+				{
+					UID:       "1",
+					DateStart: values.NewDateTime(time.Date(2024, time.April, 15, 0, 0, 0, 0, loc)),
+					DateEnd:   values.NewDateTime(time.Date(2024, time.April, 20, 0, 0, 0, 0, loc)),
+					Summary:   "Holidays",
+				},
+			},
+		},
+	}
+
+	// 2024 starts on a Monday: 104 weekend days. 11 national holidays, 14 July on a Sunday. Bridge days are the Fridays
+	// after Ascension and 15 August.
+	tests := []struct {
+		name        string
+		opts        []Option
+		want        YearStats
+		wantQueries int
+	}{
+		{name: "National holidays", want: YearStats{TotalDays: 366, WeekendDays: 104, Holidays: 11, WorkingDays: 252, BridgeDays: 2}},
+		{
+			name:        "CalDAV holidays",
+			opts:        []Option{WithCaldav(cdav), WithCaldavSummaryPattern("Holidays")},
+			want:        YearStats{TotalDays: 366, WeekendDays: 104, Holidays: 16, WorkingDays: 247, BridgeDays: 2},
+			wantQueries: 1,
+		},
+		{
+			name: "Bridge days off",
+			opts: []Option{WithBridgeDays(true)},
+			want: YearStats{TotalDays: 366, WeekendDays: 104, Holidays: 11, WorkingDays: 250, BridgeDays: 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cdav.queries = 0
+			c := New(loc, tt.opts...)
+			if got := c.YearStats(2024); got != tt.want {
+				t.Errorf("bad stats, expected:%+v ; actual:%+v", tt.want, got)
+			}
+			if cdav.queries != tt.wantQueries {
+				t.Errorf("bad caldav queries count, expected:%v ; actual:%v", tt.wantQueries, cdav.queries)
+			}
+		})
+	}
+}
+
+func TestAcademicYear(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	tests := []struct {
+		name string
+		day  time.Time
+		want int
+	}{
+		{name: "January", day: time.Date(2024, time.January, 15, 0, 0, 0, 0, loc), want: 2023},
+		{name: "July", day: time.Date(2024, time.July, 14, 0, 0, 0, 0, loc), want: 2023},
+		{name: "Last day of August", day: time.Date(2024, time.August, 31, 23, 59, 0, 0, loc), want: 2023},
+		{name: "First day of September", day: time.Date(2024, time.September, 1, 0, 0, 0, 0, loc), want: 2024},
+		{name: "December", day: time.Date(2024, time.December, 25, 0, 0, 0, 0, loc), want: 2024},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AcademicYear(tt.day); got != tt.want {
+				t.Errorf("bad academic year, expected:%v ; actual:%v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCalendar_WithClock(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	tests := []struct {
+		name             string
+		now              time.Time
+		wantWorkingDay   bool
+		wantWorkingHours bool
+	}{
+		{name: "Working hours", now: time.Date(2024, time.May, 6, 10, 0, 0, 0, loc), wantWorkingDay: true, wantWorkingHours: true},
+		{name: "Evening", now: time.Date(2024, time.May, 6, 20, 0, 0, 0, loc), wantWorkingDay: true},
+		{name: "Holiday", now: time.Date(2024, time.May, 8, 10, 0, 0, 0, loc)},
+		{name: "Weekend", now: time.Date(2024, time.May, 11, 10, 0, 0, 0, loc)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(loc, WithWorkingHours(WorkingHours{Start: 9 * time.Hour, End: 18 * time.Hour}), WithClock(func() time.Time {
+				return tt.now
+			}))
+			if !c.Now().Equal(tt.now) {
+				t.Errorf("bad current time, expected:%v ; actual:%v", tt.now, c.Now())
+			}
+			if got := c.IsWorkingDayToday(); got != tt.wantWorkingDay {
+				t.Errorf("bad working day status, expected:%v ; actual:%v", tt.wantWorkingDay, got)
+			}
+			if got := c.IsWorkingHoursNow(); got != tt.wantWorkingHours {
+				t.Errorf("bad working hours status, expected:%v ; actual:%v", tt.wantWorkingHours, got)
+			}
+		})
+	}
+}
+
+func TestCalendar_ForRange(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	// Congés from Monday 8 to Friday 12 April 2024, the range starts on Thursday 11
+	cdav := &CountingCaldav{
+		MockCaldav: MockCaldav{
+			events: []*components.Event{
+				{
+					UID:       "1",
+					DateStart: values.NewDateTime(time.Date(2024, time.April, 8, 0, 0, 0, 0, loc)),
+					DateEnd:   values.NewDateTime(time.Date(2024, time.April, 13, 0, 0, 0, 0, loc)),
+					Summary:   "Congés",
+				},
+			},
+		},
+	}
+	start := time.Date(2024, time.April, 11, 0, 0, 0, 0, loc)
+	end := time.Date(2024, time.April, 20, 0, 0, 0, 0, loc)
+	c := New(loc, WithCaldav(cdav), WithCaldavSummaryPattern("Congés")).ForRange(start, end)
+	if cdav.queries != 1 {
+		t.Fatalf("bad queries count, expected:1 ; actual:%v", cdav.queries)
+	}
+
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		want := day.Day() <= 12
+		if got := c.IsHoliday(day); got != want {
+			t.Errorf("bad holiday status of %v, expected:%v ; actual:%v", day, want, got)
+		}
+		c.IsWorkingDay(day)
+		c.IsBridgeDay(day)
+	}
+	if name, _ := c.HolidayName(start); name != "Congés" {
+		t.Errorf("bad holiday name, expected:Congés ; actual:%v", name)
+	}
+	if cdav.queries != 1 {
+		t.Errorf("days of the range should not query caldav, queries count:%v", cdav.queries)
+	}
+
+	c.IsHoliday(end.AddDate(0, 0, 5))
+	if cdav.queries != 2 {
+		t.Errorf("days out of the range should query caldav, queries count:%v", cdav.queries)
+	}
+
+	days := New(loc, WithCaldav(cdav), WithCaldavSummaryPattern("Congés")).WorkingDays(start, end)
+	if len(days) != 5 || !days[0].Equal(time.Date(2024, time.April, 15, 0, 0, 0, 0, loc)) {
+		t.Errorf("bad working days, expected the 5 days from 15 April ; actual:%v", days)
+	}
+	if cdav.queries != 3 {
+		t.Errorf("working days should be read with a single query, queries count:%v", cdav.queries)
+	}
+}
@@ -0,0 +1,117 @@
+package calendar
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// recurringDayLayout is the layout of custom holidays applying every year
+const recurringDayLayout = "01-02"
+
+// CustomHoliday is an additional fixed holiday, such as a company day off. It applies every year when Year is 0.
+type CustomHoliday struct {
+	Year  int
+	Month time.Month
+	Day   int
+	Name  string
+}
+
+type customHolidayJSON struct {
+	Date string `json:"date"`
+	Name string `json:"name"`
+}
+
+// UnmarshalJSON decodes {"date": "...", "name": "..."}, date being YYYY-MM-DD for a single year or MM-DD for every year
+func (h *CustomHoliday) UnmarshalJSON(data []byte) error {
+	var raw customHolidayJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw.Name == "" {
+		return fmt.Errorf("missing name of custom holiday '%v'", raw.Date)
+	}
+	if date, err := time.Parse(dayKeyLayout, raw.Date); err == nil {
+		*h = CustomHoliday{Year: date.Year(), Month: date.Month(), Day: date.Day(), Name: raw.Name}
+		return nil
+	}
+	date, err := time.Parse(recurringDayLayout, raw.Date)
+	if err != nil {
+		return fmt.Errorf("invalid date '%v' of custom holiday '%v', expected YYYY-MM-DD or MM-DD", raw.Date, raw.Name)
+	}
+	*h = CustomHoliday{Month: date.Month(), Day: date.Day(), Name: raw.Name}
+	return nil
+}
+
+// ParseCustomHolidays decodes a JSON array of custom holidays
+func ParseCustomHolidays(content []byte) ([]CustomHoliday, error) {
+	var holidays []CustomHoliday
+	if err := json.Unmarshal(content, &holidays); err != nil {
+		return nil, err
+	}
+	return holidays, nil
+}
+
+// LoadCustomHolidays reads a JSON array of custom holidays from path
+func LoadCustomHolidays(path string) ([]CustomHoliday, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read holidays file: %w", err)
+	}
+	holidays, err := ParseCustomHolidays(content)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode holidays file '%v': %w", path, err)
+	}
+	return holidays, nil
+}
+
+// WithCustomHolidays adds holidays to the national ones
+func WithCustomHolidays(holidays []CustomHoliday) Option {
+	return func(calendar *Calendar) {
+		calendar.customHolidays = append(calendar.customHolidays, holidays...)
+	}
+}
+
+// WithExcludedHolidays removes the holidays falling on the given days, formatted as MM-DD, such as a holiday not
+// granted by a collective agreement. Excluded days are working days unless they are weekend days.
+func WithExcludedHolidays(days ...string) Option {
+	return func(calendar *Calendar) {
+		if calendar.excludedHolidays == nil {
+			calendar.excludedHolidays = make(map[string]bool, len(days))
+		}
+		for _, day := range days {
+			calendar.excludedHolidays[day] = true
+		}
+	}
+}
+
+// withoutExcluded returns holidays without the excluded days, holidays is left untouched as it may be cached
+func (cal *Calendar) withoutExcluded(holidays []Holiday) []Holiday {
+	if len(cal.excludedHolidays) == 0 {
+		return holidays
+	}
+	result := make([]Holiday, 0, len(holidays))
+	for _, h := range holidays {
+		if !cal.excludedHolidays[h.Date.Format(recurringDayLayout)] {
+			result = append(result, h)
+		}
+	}
+	return result
+}
+
+// customHolidaysOf returns the custom holidays of the year, a recurring 29 February being skipped on non leap years
+func (cal *Calendar) customHolidaysOf(year int) []Holiday {
+	var holidays []Holiday
+	for _, h := range cal.customHolidays {
+		if h.Year != 0 && h.Year != year {
+			continue
+		}
+		date := time.Date(year, h.Month, h.Day, 0, 0, 0, 0, cal.Location)
+		if date.Month() != h.Month {
+			continue
+		}
+		holidays = append(holidays, Holiday{Date: date, Name: h.Name, Source: SourceCustom})
+	}
+	return holidays
+}
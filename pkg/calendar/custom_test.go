@@ -0,0 +1,167 @@
+package calendar
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadCustomHolidays(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "holidays.json")
+	content := `[
+		{"date": "03-17", "name": "Anniversaire de la société"},
+		{"date": "2024-08-16", "name": "Pont de l'Assomption"}
+	]`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("unable to write holidays file: %v", err)
+	}
+	holidays, err := LoadCustomHolidays(path)
+	if err != nil {
+		t.Fatalf("unable to load holidays: %v", err)
+	}
+	want := []CustomHoliday{
+		{Month: time.March, Day: 17, Name: "Anniversaire de la société"},
+		{Year: 2024, Month: time.August, Day: 16, Name: "Pont de l'Assomption"},
+	}
+	if len(holidays) != len(want) {
+		t.Fatalf("bad holidays, expected:%v ; actual:%v", want, holidays)
+	}
+	for i := range want {
+		if holidays[i] != want[i] {
+			t.Errorf("bad holiday, expected:%v ; actual:%v", want[i], holidays[i])
+		}
+	}
+
+	for _, invalid := range []string{`[{"date": "2024-13-01", "name": "a"}]`, `[{"date": "17/03", "name": "a"}]`, `[{"date": "03-17"}]`} {
+		if err := os.WriteFile(path, []byte(invalid), 0o600); err != nil {
+			t.Fatalf("unable to write holidays file: %v", err)
+		}
+		if _, err := LoadCustomHolidays(path); err == nil {
+			t.Errorf("%v should be invalid", invalid)
+		}
+	}
+}
+
+func TestCalendar_WithCustomHolidays(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	c := New(loc, WithCustomHolidays([]CustomHoliday{
+		{Month: time.March, Day: 17, Name: "Anniversaire de la société"},
+		{Year: 2024, Month: time.August, Day: 16, Name: "Pont de l'Assomption"},
+		{Month: time.February, Day: 29, Name: "Jour bissextile"},
+		{Month: time.July, Day: 14, Name: "Doublon"},
+	}))
+
+	tests := []struct {
+		name     string
+		date     time.Time
+		want     bool
+		wantName string
+	}{
+		{name: "Recurring 2024", date: time.Date(2024, time.March, 17, 0, 0, 0, 0, loc), want: true, wantName: "Anniversaire de la société"},
+		{name: "Recurring 2025", date: time.Date(2025, time.March, 17, 0, 0, 0, 0, loc), want: true, wantName: "Anniversaire de la société"},
+		{name: "Single year", date: time.Date(2024, time.August, 16, 0, 0, 0, 0, loc), want: true, wantName: "Pont de l'Assomption"},
+		{name: "Single year other year", date: time.Date(2025, time.August, 16, 0, 0, 0, 0, loc), want: false},
+		{name: "Leap day", date: time.Date(2024, time.February, 29, 0, 0, 0, 0, loc), want: true, wantName: "Jour bissextile"},
+		{name: "Leap day on non leap year", date: time.Date(2025, time.March, 1, 0, 0, 0, 0, loc), want: false},
+		{name: "Custom name over national holiday", date: time.Date(2024, time.July, 14, 0, 0, 0, 0, loc), want: true, wantName: "Doublon"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if c.IsHoliday(tt.date) != tt.want {
+				t.Errorf("IsHoliday(%v) = %v, want %v", tt.date, !tt.want, tt.want)
+			}
+			count := 0
+			for _, h := range c.GetHolidaysNamed(tt.date.Year()) {
+				if h.Date.Equal(tt.date) {
+					count++
+					if h.Name != tt.wantName {
+						t.Errorf("bad holiday name, expected:%v ; actual:%v", tt.wantName, h.Name)
+					}
+				}
+			}
+			if tt.want && count != 1 {
+				t.Errorf("%v listed %v times in holidays", tt.date, count)
+			}
+			if !tt.want && count != 0 {
+				t.Errorf("%v should not be listed in holidays", tt.date)
+			}
+		})
+	}
+}
+
+func TestCalendar_HolidaysPrecedence(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	// Saint Stephen and Good Friday are national, with the options, region and custom holidays at once
+	c := New(loc, WithRegion(RegionAlsaceMoselle), WithSaintStephen(), WithGoodFriday(), WithCustomHolidays([]CustomHoliday{
+		{Month: time.December, Day: 26, Name: "Journée de la société"},
+	}))
+
+	tests := []struct {
+		name       string
+		date       time.Time
+		wantName   string
+		wantSource string
+	}{
+		{name: "Custom over region and national", date: time.Date(2024, time.December, 26, 0, 0, 0, 0, loc), wantName: "Journée de la société", wantSource: SourceCustom},
+		{name: "Region over national", date: time.Date(2024, time.March, 29, 0, 0, 0, 0, loc), wantName: "Vendredi saint", wantSource: SourceNational},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var found []Holiday
+			for _, h := range c.HolidaysOfYear(tt.date.Year()) {
+				if h.Date.Equal(tt.date) {
+					found = append(found, h)
+				}
+			}
+			if len(found) != 1 {
+				t.Fatalf("%v should be listed once, actual:%v", tt.date, found)
+			}
+			if found[0].Name != tt.wantName {
+				t.Errorf("bad holiday name, expected:%v ; actual:%v", tt.wantName, found[0].Name)
+			}
+			if found[0].Source != tt.wantSource {
+				t.Errorf("bad holiday source, expected:%v ; actual:%v", tt.wantSource, found[0].Source)
+			}
+		})
+	}
+}
+
+func TestCalendar_WithExcludedHolidays(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	c := New(loc,
+		WithCustomHolidays([]CustomHoliday{{Month: time.March, Day: 17, Name: "Anniversaire de la société"}}),
+		WithExcludedHolidays("05-08"),
+	)
+
+	victoryDay := time.Date(2024, time.May, 8, 0, 0, 0, 0, loc)
+	if c.IsHoliday(victoryDay) {
+		t.Errorf("excluded holiday %v should not be a holiday", victoryDay)
+	}
+	if !c.IsWorkingDay(victoryDay) {
+		t.Errorf("excluded holiday %v should be a working day", victoryDay)
+	}
+	for _, h := range c.GetHolidaysNamed(2024) {
+		if h.Date.Equal(victoryDay) {
+			t.Errorf("excluded holiday %v should not be listed", victoryDay)
+		}
+	}
+	if !c.IsHoliday(time.Date(2024, time.May, 1, 0, 0, 0, 0, loc)) {
+		t.Errorf("1 May should still be a holiday")
+	}
+	if !c.IsHoliday(time.Date(2024, time.March, 17, 0, 0, 0, 0, loc)) {
+		t.Errorf("custom holiday should still be a holiday")
+	}
+}
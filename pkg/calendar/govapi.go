@@ -0,0 +1,164 @@
+package calendar
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// GovHolidayAPIURL is the official French holidays API, see https://calendrier.api.gouv.fr/jours-feries/
+	GovHolidayAPIURL = "https://calendrier.api.gouv.fr/jours-feries"
+	govAPITimeout    = 10 * time.Second
+	// govAPIRetryDelay is the delay before fetching again holidays of a year after a failure
+	govAPIRetryDelay = time.Hour
+)
+
+// govHolidayAPI fetches official holidays per region and year, successful fetches are kept forever
+type govHolidayAPI struct {
+	url    string
+	client *http.Client
+
+	mu       sync.Mutex
+	years    map[string]map[string]string
+	failures map[string]time.Time
+	// inflight are the fetches in progress, closed when done, so that concurrent calls wait for a single fetch
+	inflight map[string]chan struct{}
+}
+
+func newGovHolidayAPI(url string) *govHolidayAPI {
+	return &govHolidayAPI{
+		url:      url,
+		client:   &http.Client{Timeout: govAPITimeout},
+		years:    make(map[string]map[string]string),
+		failures: make(map[string]time.Time),
+		inflight: make(map[string]chan struct{}),
+	}
+}
+
+// WithGovHolidayAPI uses the official holidays API as the authoritative source of national holidays when enabled.
+// Computed holidays are used when the API can't be reached, and a warning is logged when they disagree.
+func WithGovHolidayAPI(enabled bool) Option {
+	return func(calendar *Calendar) {
+		if !enabled {
+			calendar.govAPI = nil
+			return
+		}
+		if calendar.govAPI == nil {
+			calendar.govAPI = newGovHolidayAPI(GovHolidayAPIURL)
+		}
+	}
+}
+
+// WithGovHolidayAPIURL changes the URL of the official holidays API, GovHolidayAPIURL by default, and enables it
+func WithGovHolidayAPIURL(url string) Option {
+	return func(calendar *Calendar) {
+		calendar.govAPI = newGovHolidayAPI(url)
+	}
+}
+
+// get returns the official holidays of the year in region, by YYYY-MM-DD day. fetched is true if the API has been
+// queried by this call. Holidays are nil, without error, while waiting to retry after a failure. The lock isn't held
+// during the fetch: concurrent calls for the same year and region wait for its result, other calls aren't blocked.
+func (api *govHolidayAPI) get(year int, region Region) (days map[string]string, fetched bool, err error) {
+	key := fmt.Sprintf("%v/%d", region, year)
+
+	api.mu.Lock()
+	for {
+		if days, ok := api.years[key]; ok {
+			api.mu.Unlock()
+			return days, false, nil
+		}
+		if failure, ok := api.failures[key]; ok && time.Since(failure) < govAPIRetryDelay {
+			api.mu.Unlock()
+			return nil, false, nil
+		}
+		done, ok := api.inflight[key]
+		if !ok {
+			break
+		}
+		api.mu.Unlock()
+		<-done
+		api.mu.Lock()
+	}
+	done := make(chan struct{})
+	api.inflight[key] = done
+	api.mu.Unlock()
+
+	days, err = api.fetch(year, region)
+
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	delete(api.inflight, key)
+	close(done)
+	if err != nil {
+		api.failures[key] = time.Now()
+		return nil, true, err
+	}
+	delete(api.failures, key)
+	api.years[key] = days
+	return days, true, nil
+}
+
+func (api *govHolidayAPI) fetch(year int, region Region) (map[string]string, error) {
+	url := fmt.Sprintf("%v/%v/%d.json", api.url, region.definition().zone, year)
+	resp, err := api.client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query %v: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status of %v: %v", url, resp.Status)
+	}
+	var days map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&days); err != nil {
+		return nil, fmt.Errorf("unable to decode %v: %w", url, err)
+	}
+	return days, nil
+}
+
+// officialHolidays replaces the national holidays of computed by the official ones. Computed names are kept for
+// days in both lists, custom and unofficial local holidays are kept. Differences are logged if check is set.
+func (cal *Calendar) officialHolidays(computed []Holiday, official map[string]string, check bool) []Holiday {
+	national := make(map[string]Holiday, len(computed))
+	custom := make(map[string]bool)
+	holidays := make([]Holiday, 0, len(official))
+	for _, h := range computed {
+		day := h.Date.Format(dayKeyLayout)
+		if cal.isUnofficial(h) {
+			custom[day] = true
+			holidays = append(holidays, h)
+			continue
+		}
+		national[day] = h
+		if _, ok := official[day]; !ok && check {
+			cal.logger.Warnf("computed holiday '%v' on %v is not an official holiday", h.Name, day)
+		}
+	}
+
+	for day, name := range official {
+		if h, ok := national[day]; ok {
+			holidays = append(holidays, h)
+			continue
+		}
+		if custom[day] {
+			continue
+		}
+		date, err := time.ParseInLocation(dayKeyLayout, day, cal.Location)
+		if err != nil {
+			cal.logger.Warnf("invalid official holiday date '%v': %v", day, err)
+			continue
+		}
+		if check {
+			cal.logger.Warnf("official holiday '%v' on %v is not computed", name, day)
+		}
+		holidays = append(holidays, Holiday{Date: date, Name: name, Source: SourceNational})
+	}
+	sort.Slice(holidays, func(i, j int) bool {
+		return holidays[i].Date.Before(holidays[j].Date)
+	})
+	return holidays
+}
@@ -0,0 +1,173 @@
+package calendar
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+const officialHolidays2024 = `{"2024-01-01": "1er janvier", "2024-04-01": "Lundi de Pâques", "2024-05-01": "1er mai",
+"2024-05-08": "8 mai", "2024-05-09": "Ascension", "2024-05-20": "Lundi de Pentecôte", "2024-07-14": "14 juillet",
+"2024-08-15": "Assomption", "2024-11-01": "Toussaint", "2024-11-11": "11 novembre", "2024-12-25": "Jour de Noël"}`
+
+// Pentecost Monday missing, extra holiday on 2 January
+const officialHolidays2023 = `{"2023-01-01": "1er janvier", "2023-01-02": "Jour exceptionnel", "2023-04-10": "Lundi de Pâques",
+"2023-05-01": "1er mai", "2023-05-08": "8 mai", "2023-05-18": "Ascension", "2023-07-14": "14 juillet",
+"2023-08-15": "Assomption", "2023-11-01": "Toussaint", "2023-11-11": "11 novembre", "2023-12-25": "Jour de Noël"}`
+
+func TestCalendar_WithGovHolidayAPI(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	queries := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries[r.URL.Path]++
+		switch r.URL.Path {
+		case "/metropole/2024.json":
+			_, _ = w.Write([]byte(officialHolidays2024))
+		case "/metropole/2023.json":
+			_, _ = w.Write([]byte(officialHolidays2023))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name         string
+		year         int
+		wantCount    int
+		wantHoliday  time.Time
+		wantNot      time.Time
+		wantWarnings int
+	}{
+		{
+			name:         "Official and computed holidays agree",
+			year:         2024,
+			wantCount:    11,
+			wantHoliday:  time.Date(2024, time.May, 20, 0, 0, 0, 0, loc),
+			wantNot:      time.Date(2024, time.May, 21, 0, 0, 0, 0, loc),
+			wantWarnings: 0,
+		},
+		{
+			name:         "Official holidays are authoritative",
+			year:         2023,
+			wantCount:    11,
+			wantHoliday:  time.Date(2023, time.January, 2, 0, 0, 0, 0, loc),
+			wantNot:      time.Date(2023, time.May, 29, 0, 0, 0, 0, loc),
+			wantWarnings: 2,
+		},
+		{
+			name:         "Computed holidays on failure",
+			year:         2025,
+			wantCount:    11,
+			wantHoliday:  time.Date(2025, time.June, 9, 0, 0, 0, 0, loc),
+			wantNot:      time.Date(2025, time.June, 10, 0, 0, 0, 0, loc),
+			wantWarnings: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := recordingLogger{}
+			c := New(loc, WithGovHolidayAPIURL(server.URL), WithLogger(&logger))
+
+			holidays := c.GetHolidaysNamed(tt.year)
+			if len(holidays) != tt.wantCount {
+				t.Errorf("bad holidays count, expected:%v ; actual:%v", tt.wantCount, len(holidays))
+			}
+			if !c.IsHoliday(tt.wantHoliday) {
+				t.Errorf("%v should be a holiday", tt.wantHoliday)
+			}
+			if c.IsHoliday(tt.wantNot) {
+				t.Errorf("%v should not be a holiday", tt.wantNot)
+			}
+			if len(logger.warnings) != tt.wantWarnings {
+				t.Errorf("bad warnings, expected:%v ; actual:%v", tt.wantWarnings, logger.warnings)
+			}
+		})
+	}
+
+	for path, count := range queries {
+		if count != 1 {
+			t.Errorf("%v queried %d times, expected once", path, count)
+		}
+	}
+}
+
+func TestCalendar_WithGovHolidayAPI_KeepsNames(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(officialHolidays2024))
+	}))
+	defer server.Close()
+
+	c := New(loc, WithGovHolidayAPIURL(server.URL))
+	h, ok := c.HolidayAt(time.Date(2024, time.December, 25, 0, 0, 0, 0, loc))
+	if !ok || h.Name != "Noël" || h.Key != "christmas" {
+		t.Errorf("computed name and key should be kept: %+v", h)
+	}
+}
+
+func TestGovHolidayAPI_ConcurrentGet(t *testing.T) {
+	release := make(chan struct{})
+	var mu sync.Mutex
+	queries := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries[r.URL.Path]++
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/metropole/2024.json":
+			<-release
+			_, _ = w.Write([]byte(officialHolidays2024))
+		default:
+			_, _ = w.Write([]byte(officialHolidays2023))
+		}
+	}))
+	defer server.Close()
+	api := newGovHolidayAPI(server.URL)
+
+	var wg sync.WaitGroup
+	results := make([]map[string]string, 2)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _, _ = api.get(2024, RegionMetropole)
+		}(i)
+	}
+
+	// the slow fetch of 2024 must not block other years
+	other := make(chan map[string]string)
+	go func() {
+		days, _, _ := api.get(2023, RegionMetropole)
+		other <- days
+	}()
+	select {
+	case days := <-other:
+		if len(days) != 11 {
+			t.Errorf("bad official holidays of 2023: %v", days)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("a fetch in progress should not block other years")
+	}
+
+	close(release)
+	wg.Wait()
+	for i, days := range results {
+		if len(days) != 11 {
+			t.Errorf("bad official holidays of 2024 for call %d: %v", i, days)
+		}
+	}
+	if queries["/metropole/2024.json"] != 1 {
+		t.Errorf("concurrent calls should share a single fetch, actual:%v", queries["/metropole/2024.json"])
+	}
+}
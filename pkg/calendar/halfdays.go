@@ -0,0 +1,74 @@
+package calendar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Types of days, see DayType
+const (
+	DayFull = "full"
+	DayHalf = "half"
+	DayOff  = "off"
+)
+
+// WithHalfDays sets the days, formatted as MM-DD, whose afternoon is off, such as Christmas Eve in some collective
+// agreements. Half-days remain working days, unless they are holidays or weekend days.
+func WithHalfDays(days ...string) Option {
+	return func(calendar *Calendar) {
+		if calendar.halfDays == nil {
+			calendar.halfDays = make(map[string]bool, len(days))
+		}
+		for _, day := range days {
+			calendar.halfDays[day] = true
+		}
+	}
+}
+
+// ParseHalfDays parses a comma separated list of MM-DD days, such as 12-24,12-31, see WithHalfDays
+func ParseHalfDays(value string) ([]string, error) {
+	var days []string
+	for _, day := range strings.Split(value, ",") {
+		day = strings.TrimSpace(day)
+		if day == "" {
+			continue
+		}
+		if _, err := time.Parse(recurringDayLayout, day); err != nil {
+			return nil, fmt.Errorf("invalid half-day '%v', expected MM-DD", day)
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+// IsHalfDay returns true if date is one of the half-days, see WithHalfDays, whether it's a working day or not
+func (cal *Calendar) IsHalfDay(date time.Time) bool {
+	return cal.halfDays[cal.midnight(date).Format(recurringDayLayout)]
+}
+
+// DayType returns DayOff if date isn't a working day, DayHalf if it's a half-day, see WithHalfDays, and DayFull
+// otherwise
+func (cal *Calendar) DayType(date time.Time) string {
+	if !cal.IsWorkingDay(date) {
+		return DayOff
+	}
+	if cal.IsHalfDay(date) {
+		return DayHalf
+	}
+	return DayFull
+}
+
+// CountEffectiveWorkingDays returns the number of working days between start and end inclusive, half-days counting
+// for 0.5, see CountWorkingDays to count them as whole days
+func (cal *Calendar) CountEffectiveWorkingDays(start, end time.Time) float64 {
+	var count float64
+	for _, day := range cal.WorkingDays(start, end) {
+		if cal.IsHalfDay(day) {
+			count += 0.5
+		} else {
+			count++
+		}
+	}
+	return count
+}
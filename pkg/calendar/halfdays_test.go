@@ -0,0 +1,53 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalendar_WithHalfDays(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	c := New(loc, WithHalfDays("12-24", "12-31"))
+
+	tests := []struct {
+		name string
+		date time.Time
+		want string
+	}{
+		{name: "Half-day", date: time.Date(2024, time.December, 24, 15, 0, 0, 0, loc), want: DayHalf},
+		{name: "Full day", date: time.Date(2024, time.December, 23, 0, 0, 0, 0, loc), want: DayFull},
+		{name: "Holiday", date: time.Date(2024, time.December, 25, 0, 0, 0, 0, loc), want: DayOff},
+		{name: "Half-day on weekend", date: time.Date(2022, time.December, 24, 0, 0, 0, 0, loc), want: DayOff},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.DayType(tt.date); got != tt.want {
+				t.Errorf("bad day type, expected:%v ; actual:%v", tt.want, got)
+			}
+		})
+	}
+
+	// From Monday 23 to Tuesday 31 December 2024: 6 working days, 2 of them half-days
+	start := time.Date(2024, time.December, 23, 0, 0, 0, 0, loc)
+	end := time.Date(2024, time.December, 31, 0, 0, 0, 0, loc)
+	if got := c.CountWorkingDays(start, end); got != 6 {
+		t.Errorf("bad working days count, expected:6 ; actual:%v", got)
+	}
+	if got := c.CountEffectiveWorkingDays(start, end); got != 5 {
+		t.Errorf("bad effective working days count, expected:5 ; actual:%v", got)
+	}
+}
+
+func TestParseHalfDays(t *testing.T) {
+	days, err := ParseHalfDays("12-24, 12-31")
+	if err != nil || len(days) != 2 || days[0] != "12-24" || days[1] != "12-31" {
+		t.Errorf("ParseHalfDays() got = (%v, %v), want ([12-24 12-31], nil)", days, err)
+	}
+	if _, err := ParseHalfDays("24/12"); err == nil {
+		t.Error("ParseHalfDays() should fail on invalid day")
+	}
+}
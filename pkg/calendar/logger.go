@@ -0,0 +1,28 @@
+package calendar
+
+// Logger is the logging interface used by the calendar, zap.SugaredLogger implements it
+type Logger interface {
+	Debugf(template string, args ...interface{})
+	Warnf(template string, args ...interface{})
+	Errorf(template string, args ...interface{})
+}
+
+// noopLogger discards all logs, it's the default Logger
+type noopLogger struct{}
+
+func (noopLogger) Debugf(string, ...interface{}) {}
+
+func (noopLogger) Warnf(string, ...interface{}) {}
+
+func (noopLogger) Errorf(string, ...interface{}) {}
+
+// WithLogger logs calendar errors, such as CalDAV failures, with logger. Logs are discarded by default or if logger
+// is nil.
+func WithLogger(logger Logger) Option {
+	return func(calendar *Calendar) {
+		if logger == nil {
+			logger = noopLogger{}
+		}
+		calendar.logger = logger
+	}
+}
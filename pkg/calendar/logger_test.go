@@ -0,0 +1,55 @@
+package calendar
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+type recordingLogger struct {
+	debugs   []string
+	warnings []string
+	errors   []string
+}
+
+func (l *recordingLogger) Debugf(template string, args ...interface{}) {
+	l.debugs = append(l.debugs, fmt.Sprintf(template, args...))
+}
+
+func (l *recordingLogger) Warnf(template string, args ...interface{}) {
+	l.warnings = append(l.warnings, fmt.Sprintf(template, args...))
+}
+
+func (l *recordingLogger) Errorf(template string, args ...interface{}) {
+	l.errors = append(l.errors, fmt.Sprintf(template, args...))
+}
+
+func TestWithLogger(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	day := time.Date(2022, time.April, 20, 0, 0, 0, 0, loc)
+
+	// No logger configured, errors are discarded
+	c := New(loc, WithCaldav(&FailingCaldav{}))
+	if c.IsHoliday(day) {
+		t.Errorf("%v should not be a holiday", day)
+	}
+
+	// nil logger, errors are discarded
+	c = New(loc, WithCaldav(&FailingCaldav{}), WithLogger(nil))
+	if c.IsHoliday(day) {
+		t.Errorf("%v should not be a holiday", day)
+	}
+
+	logger := recordingLogger{}
+	c = New(loc, WithCaldav(&FailingCaldav{}), WithLogger(&logger))
+	if c.IsHoliday(day) {
+		t.Errorf("%v should not be a holiday", day)
+	}
+	if len(logger.errors) != 1 {
+		t.Errorf("caldav error should be logged, got %v", logger.errors)
+	}
+}
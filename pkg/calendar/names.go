@@ -0,0 +1,50 @@
+package calendar
+
+import "time"
+
+// Language of holiday names
+type Language string
+
+const (
+	French  Language = "fr"
+	English Language = "en"
+)
+
+// Languages are the supported languages, French first as default
+var Languages = []Language{French, English}
+
+// holidayNames are the names of national and regional holidays by key and language
+var holidayNames = map[string]map[Language]string{
+	"new_year":         {French: "Jour de l'an", English: "New Year's Day"},
+	"good_friday":      {French: "Vendredi saint", English: "Good Friday"},
+	"easter_monday":    {French: "Lundi de Pâques", English: "Easter Monday"},
+	"labour_day":       {French: "Fête du travail", English: "Labour Day"},
+	"victory_day":      {French: "Victoire 1945", English: "Victory in Europe Day"},
+	"ascension":        {French: "Ascension", English: "Ascension Day"},
+	"pentecost_monday": {French: "Lundi de Pentecôte", English: "Whit Monday"},
+	"national_day":     {French: "Fête nationale", English: "Bastille Day"},
+	"assumption":       {French: "Assomption", English: "Assumption Day"},
+	"all_saints":       {French: "Toussaint", English: "All Saints' Day"},
+	"armistice":        {French: "Armistice 1918", English: "Armistice Day"},
+	"christmas":        {French: "Noël", English: "Christmas Day"},
+	"saint_stephen":    {French: "Saint Étienne", English: "St. Stephen's Day"},
+}
+
+// LocalizedName returns the name in lang of the national holiday key, false if the key or the language is unknown
+func LocalizedName(key string, lang Language) (string, bool) {
+	name, ok := holidayNames[key][lang]
+	return name, ok
+}
+
+// Localized returns h named in lang. Holidays without key, such as CalDAV or custom ones, keep their name.
+func (h Holiday) Localized(lang Language) Holiday {
+	if name, ok := LocalizedName(h.Key, lang); ok {
+		h.Name = name
+	}
+	return h
+}
+
+// nationalHoliday returns the national holiday key at date, named in French
+func nationalHoliday(date time.Time, key string) Holiday {
+	return Holiday{Date: date, Name: holidayNames[key][French], Key: key, Source: SourceNational}
+}
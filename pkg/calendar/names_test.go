@@ -0,0 +1,50 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHoliday_Localized(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	c := New(loc, WithRegion(RegionAlsaceMoselle))
+	allSaints := time.Date(2024, time.November, 1, 0, 0, 0, 0, loc)
+
+	tests := []struct {
+		name string
+		lang Language
+		want string
+	}{
+		{name: "French", lang: French, want: "Toussaint"},
+		{name: "English", lang: English, want: "All Saints' Day"},
+		{name: "Unknown language", lang: Language("de"), want: "Toussaint"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, ok := c.HolidayAt(allSaints)
+			if !ok {
+				t.Fatalf("%v should be a holiday", allSaints)
+			}
+			if got := h.Localized(tt.lang).Name; got != tt.want {
+				t.Errorf("bad holiday name, expected:%v ; actual:%v", tt.want, got)
+			}
+		})
+	}
+
+	for _, h := range c.GetHolidaysNamed(2024) {
+		for _, lang := range Languages {
+			if _, ok := LocalizedName(h.Key, lang); !ok {
+				t.Errorf("missing %v name of holiday %v", lang, h.Key)
+			}
+		}
+	}
+
+	custom := Holiday{Date: allSaints, Name: "Congés"}
+	if got := custom.Localized(English).Name; got != "Congés" {
+		t.Errorf("holiday without key should keep its name, got %v", got)
+	}
+}
@@ -0,0 +1,86 @@
+package calendar
+
+import (
+	"sort"
+	"time"
+)
+
+// WithWeekendObservance adds an observed holiday on the nearest working day of each holiday falling on a weekend day,
+// see WithWeekend, when enabled: by default, the Friday before a Saturday holiday and the Monday after a Sunday
+// holiday. French law doesn't move holidays, but some private contracts do.
+func WithWeekendObservance(observance bool) Option {
+	return func(calendar *Calendar) {
+		calendar.weekendObservance = observance
+	}
+}
+
+// baseHolidays returns the holidays of the year returned by GetHolidaysNamed, without observed ones
+func (cal *Calendar) baseHolidays(year int) []Holiday {
+	holidays := cal.holidayCache.get(year, cal.computeHolidays)
+	if cal.govAPI != nil && cal.nationalHolidays {
+		official, fetched, err := cal.govAPI.get(year, cal.region)
+		if err != nil {
+			cal.logger.Warnf("unable to fetch official holidays of %d, use computed ones: %v", year, err)
+		} else if official != nil {
+			holidays = cal.officialHolidays(holidays, official, fetched)
+		}
+	}
+	return cal.withoutExcluded(holidays)
+}
+
+// withObserved returns holidays, those of the year, with the observed days falling in the year. A weekend holiday of
+// the previous or next year may be observed in the year, such as a Saturday 1 January observed on 31 December.
+// holidays is left untouched as it may be cached.
+func (cal *Calendar) withObserved(year int, holidays []Holiday) []Holiday {
+	if !cal.weekendObservance {
+		return holidays
+	}
+	candidates := append(make([]Holiday, 0, len(holidays)), holidays...)
+	for _, y := range []int{year - 1, year + 1} {
+		if ValidYear(y) {
+			candidates = append(cal.baseHolidays(y), candidates...)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Date.Before(candidates[j].Date)
+	})
+
+	days := make(map[time.Time]bool, len(candidates))
+	for _, h := range candidates {
+		days[h.Date] = true
+	}
+	result := append(make([]Holiday, 0, len(holidays)), holidays...)
+	for _, h := range candidates {
+		if !cal.isWeekend(h.Date) {
+			continue
+		}
+		observed, ok := cal.observedDay(h.Date, days)
+		if !ok {
+			continue
+		}
+		days[observed] = true
+		if observed.Year() != year {
+			continue
+		}
+		h.Date = observed
+		h.Observed = true
+		result = append(result, h)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Date.Before(result[j].Date)
+	})
+	return result
+}
+
+// observedDay returns the nearest day of day that is neither a weekend day nor one of holidays, the following one
+// when two days are as near. false is returned if no such day is found within a week.
+func (cal *Calendar) observedDay(day time.Time, holidays map[time.Time]bool) (time.Time, bool) {
+	for n := 1; n < 7; n++ {
+		for _, d := range []time.Time{day.AddDate(0, 0, n), day.AddDate(0, 0, -n)} {
+			if !cal.isWeekend(d) && !holidays[d] {
+				return d, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
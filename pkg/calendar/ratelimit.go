@@ -0,0 +1,96 @@
+package calendar
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dolanor/caldav-go/caldav"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// defaultRetryAfter is the pause after a 429 response without a valid Retry-After header
+const defaultRetryAfter = time.Minute
+
+// ErrRateLimited is returned, without querying the CalDAV server, until the end of the pause it asked for
+var ErrRateLimited = errors.New("caldav server rate limit")
+
+// WithRateLimitHook calls hook each time the CalDAV server replies 429 Too Many Requests, with the end of the pause
+func WithRateLimitHook(hook func(until time.Time)) CaldavOption {
+	return func(config *caldavConfig) {
+		config.rateLimitHook = hook
+	}
+}
+
+// rateLimitTransport pauses CalDAV requests after a 429 response, until the time given by its Retry-After header
+type rateLimitTransport struct {
+	next   http.RoundTripper
+	now    func() time.Time
+	hook   func(until time.Time)
+	logger Logger
+
+	mu    sync.Mutex
+	until time.Time
+}
+
+// pausedUntil returns the end of the current pause, true if queries are paused
+func (t *rateLimitTransport) pausedUntil() (time.Time, bool) {
+	t.mu.Lock()
+	until := t.until
+	t.mu.Unlock()
+	return until, t.now().Before(until)
+}
+
+func (t *rateLimitTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if until, paused := t.pausedUntil(); paused {
+		return nil, fmt.Errorf("%w, paused until %v", ErrRateLimited, until)
+	}
+
+	resp, err := t.next.RoundTrip(r)
+	if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+		return resp, err
+	}
+	until := t.now().Add(retryAfter(resp.Header.Get("Retry-After"), t.now()))
+	t.mu.Lock()
+	if until.After(t.until) {
+		t.until = until
+	}
+	t.mu.Unlock()
+	t.logger.Warnf("caldav server rate limit, pause queries until %v", until)
+	if t.hook != nil {
+		t.hook(until)
+	}
+	return resp, nil
+}
+
+// pausable is a Caldav whose queries are paused after a 429 response, such as the client returned by NewCaldav. The
+// CalDAV client wraps transport errors without unwrapping them, so that ErrRateLimited can't be found in its errors.
+type pausable interface {
+	pausedUntil() (time.Time, bool)
+}
+
+// rateLimitedCaldav is the CalDAV client of NewCaldav, paused by its rateLimitTransport
+type rateLimitedCaldav struct {
+	*caldav.Client
+	transport *rateLimitTransport
+}
+
+func (c *rateLimitedCaldav) pausedUntil() (time.Time, bool) {
+	return c.transport.pausedUntil()
+}
+
+// retryAfter returns the delay of a Retry-After header value, in seconds or as an HTTP date, defaultRetryAfter if
+// missing or invalid
+func retryAfter(value string, now time.Time) time.Duration {
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if d := date.Sub(now); d > 0 {
+			return d
+		}
+		return 0
+	}
+	return defaultRetryAfter
+}
@@ -0,0 +1,90 @@
+package calendar
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimitTransport(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("Retry-After", "120")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	now := time.Date(2024, time.May, 7, 10, 0, 0, 0, time.UTC)
+	var hits []time.Time
+	logger := &recordingLogger{}
+	transport := &rateLimitTransport{
+		next:   http.DefaultTransport,
+		now:    func() time.Time { return now },
+		hook:   func(until time.Time) { hits = append(hits, until) },
+		logger: logger,
+	}
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("bad status code, expected:%v ; actual:%v", http.StatusTooManyRequests, resp.StatusCode)
+	}
+	wantUntil := now.Add(2 * time.Minute)
+	if len(hits) != 1 || !hits[0].Equal(wantUntil) {
+		t.Errorf("bad rate limit hook calls, expected:%v ; actual:%v", wantUntil, hits)
+	}
+	if len(logger.warnings) != 1 {
+		t.Errorf("a warning should be logged: %v", logger.warnings)
+	}
+
+	now = now.Add(time.Minute)
+	if _, err := client.Get(server.URL); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("queries should be paused, got error %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("server should not be queried while paused: %d requests", requests)
+	}
+
+	now = now.Add(time.Minute)
+	resp, err = client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("queries should resume after the pause: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || requests != 2 {
+		t.Errorf("server should be queried after the pause: status %v, %d requests", resp.StatusCode, requests)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, time.May, 7, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"30", 30 * time.Second},
+		{now.Add(5 * time.Minute).Format(http.TimeFormat), 5 * time.Minute},
+		{now.Add(-5 * time.Minute).Format(http.TimeFormat), 0},
+		{"", defaultRetryAfter},
+		{"soon", defaultRetryAfter},
+		{"-1", defaultRetryAfter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := retryAfter(tt.value, now); got != tt.want {
+				t.Errorf("bad delay, expected:%v ; actual:%v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package calendar
+
+import "time"
+
+// Region identifies a set of local holidays in addition to the national ones
+type Region string
+
+const (
+	RegionMetropole     Region = "metropole"
+	RegionAlsaceMoselle Region = "alsace-moselle"
+	RegionCorse         Region = "corse"
+)
+
+// Regions lists all supported regions
+var Regions = []Region{RegionMetropole, RegionAlsaceMoselle, RegionCorse}
+
+// WithRegion adds local holidays of the region. Default to RegionMetropole, national holidays only.
+func WithRegion(region Region) Option {
+	return func(calendar *Calendar) {
+		calendar.region = region
+	}
+}
+
+// regionHoliday is a local holiday on a fixed day, or relative to Easter when month is 0
+type regionHoliday struct {
+	key          string
+	month        time.Month
+	day          int
+	easterOffset int
+}
+
+// regionDefinition describes the local holidays of a region
+type regionDefinition struct {
+	// zone is the region of the official holidays API
+	zone Region
+	// unofficial is set when local holidays aren't listed by the official holidays API, such as patron saint days
+	unofficial bool
+	holidays   []regionHoliday
+}
+
+// regions are the definitions of supported regions, adding a region only requires a new entry and the names of its
+// holidays
+var regions = map[Region]regionDefinition{
+	RegionMetropole: {zone: RegionMetropole},
+	RegionAlsaceMoselle: {
+		zone: RegionAlsaceMoselle,
+		holidays: []regionHoliday{
+			{key: "good_friday", easterOffset: -2},
+			{key: "saint_stephen", month: time.December, day: 26},
+		},
+	},
+	// Corsica has no public holiday of its own
+	RegionCorse: {zone: RegionMetropole},
+}
+
+// definition returns the definition of the region, an unknown region has no local holidays
+func (r Region) definition() regionDefinition {
+	if def, ok := regions[r]; ok {
+		return def
+	}
+	return regionDefinition{zone: r}
+}
+
+// date returns the day of the holiday in year
+func (h regionHoliday) date(cal *Calendar, year int) time.Time {
+	if h.month == 0 {
+		return cal.GetEasterDay(year).AddDate(0, 0, h.easterOffset)
+	}
+	return time.Date(year, h.month, h.day, 0, 0, 0, 0, cal.Location)
+}
+
+// regionHolidays returns the local holidays of the calendar region in year
+func (cal *Calendar) regionHolidays(year int) []Holiday {
+	def := cal.region.definition()
+	holidays := make([]Holiday, 0, len(def.holidays))
+	for _, h := range def.holidays {
+		holidays = append(holidays, nationalHoliday(h.date(cal, year), h.key))
+	}
+	return holidays
+}
+
+// isUnofficial returns true if h isn't expected in the official holidays API: custom holidays and local holidays of
+// regions not listed by the API
+func (cal *Calendar) isUnofficial(h Holiday) bool {
+	if h.Key == "" {
+		return true
+	}
+	def := cal.region.definition()
+	if !def.unofficial {
+		return false
+	}
+	for _, rh := range def.holidays {
+		if rh.key == h.Key {
+			return true
+		}
+	}
+	return false
+}
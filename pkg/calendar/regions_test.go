@@ -0,0 +1,62 @@
+package calendar
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCalendar_WithRegion_Corse(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	corse := New(loc, WithRegion(RegionCorse))
+	metropole := New(loc)
+	for year := 2024; year <= 2025; year++ {
+		if got, want := corse.GetHolidaysNamed(year), metropole.GetHolidaysNamed(year); !reflect.DeepEqual(got, want) {
+			t.Errorf("holidays of corse should be the national ones, expected:%v ; actual:%v", want, got)
+		}
+	}
+	if corse.IsHoliday(time.Date(2025, time.January, 27, 0, 0, 0, 0, loc)) {
+		t.Errorf("27 January should not be a holiday in corse")
+	}
+}
+
+func TestCalendar_WithRegion_GovHolidayAPI(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		_, _ = w.Write([]byte(officialHolidays2024))
+	}))
+	defer server.Close()
+
+	// a region whose local holiday isn't listed by the official holidays API
+	const patron Region = "patron"
+	regions[patron] = regionDefinition{
+		zone:       RegionMetropole,
+		unofficial: true,
+		holidays:   []regionHoliday{{key: "saint_stephen", month: time.January, day: 27}},
+	}
+	defer delete(regions, patron)
+
+	logger := &recordingLogger{}
+	c := New(loc, WithRegion(patron), WithGovHolidayAPIURL(server.URL), WithLogger(logger))
+	if !c.IsHoliday(time.Date(2024, time.January, 27, 0, 0, 0, 0, loc)) {
+		t.Errorf("unofficial local holiday should be kept with the official holidays API")
+	}
+	if len(paths) != 1 || paths[0] != "/metropole/2024.json" {
+		t.Errorf("official holidays of the region should be the metropole ones: %v", paths)
+	}
+	if len(logger.warnings) != 0 {
+		t.Errorf("no warning expected for unofficial local holidays: %v", logger.warnings)
+	}
+}
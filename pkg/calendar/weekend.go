@@ -0,0 +1,66 @@
+package calendar
+
+import "time"
+
+// defaultWeekend is the weekend unless WithWeekend is given
+var defaultWeekend = map[time.Weekday]bool{time.Saturday: true, time.Sunday: true}
+
+// WithWeekend sets the days of the weekend, Saturday and Sunday by default. Weekend days are never working days.
+func WithWeekend(days ...time.Weekday) Option {
+	return func(calendar *Calendar) {
+		weekend := make(map[time.Weekday]bool, len(days))
+		for _, d := range days {
+			weekend[d] = true
+		}
+		calendar.weekend = weekend
+	}
+}
+
+// WithWeekdays sets the working weekdays, such as Monday to Thursday for a 4-day week, every other day being a
+// weekend day, see WithWeekend. Days off of the week are neither working days nor holidays.
+func WithWeekdays(days ...time.Weekday) Option {
+	return func(calendar *Calendar) {
+		weekend := make(map[time.Weekday]bool, 7)
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			weekend[d] = true
+		}
+		for _, d := range days {
+			delete(weekend, d)
+		}
+		calendar.weekend = weekend
+	}
+}
+
+// WithSaturdayWorking makes Saturday a working day if working is true, or a weekend day otherwise, leaving the other
+// days of the weekend, see WithWeekend and WithWeekdays, unchanged: Sunday stays off by default. Bridge days follow:
+// the Friday after a Thursday holiday isn't a bridge day when Saturday is a working day, see BridgeHoliday.
+func WithSaturdayWorking(working bool) Option {
+	return func(calendar *Calendar) {
+		weekend := make(map[time.Weekday]bool, len(calendar.weekend)+1)
+		for d, off := range calendar.weekend {
+			weekend[d] = off
+		}
+		if working {
+			delete(weekend, time.Saturday)
+		} else {
+			weekend[time.Saturday] = true
+		}
+		calendar.weekend = weekend
+	}
+}
+
+// isWeekend returns true if day is a weekend day, see WithWeekend
+func (cal *Calendar) isWeekend(day time.Time) bool {
+	return cal.weekend[day.Weekday()]
+}
+
+// GetEffectiveHolidays returns the holidays of year that fall on a weekday, those that actually grant a day off
+func (cal *Calendar) GetEffectiveHolidays(year int) []Holiday {
+	var holidays []Holiday
+	for _, h := range cal.GetHolidaysNamed(year) {
+		if !cal.isWeekend(h.Date) {
+			holidays = append(holidays, h)
+		}
+	}
+	return holidays
+}
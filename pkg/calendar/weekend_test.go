@@ -0,0 +1,132 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalendar_GetEffectiveHolidays(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	// In 2022, 1 January is a Saturday, 1 May, 8 May and 25 December are Sundays, 11 November is a Friday
+	tests := []struct {
+		name       string
+		opts       []Option
+		want       int
+		weekendDay time.Time
+	}{
+		{name: "Default weekend", want: 7, weekendDay: time.Date(2022, time.May, 1, 0, 0, 0, 0, loc)},
+		{name: "Friday and Saturday weekend", opts: []Option{WithWeekend(time.Friday, time.Saturday)}, want: 9,
+			weekendDay: time.Date(2022, time.November, 11, 0, 0, 0, 0, loc)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(loc, tt.opts...)
+			holidays := c.GetEffectiveHolidays(2022)
+			if len(holidays) != tt.want {
+				t.Errorf("bad effective holidays count, expected:%v ; actual:%v (%v)", tt.want, len(holidays), holidays)
+			}
+			for _, h := range holidays {
+				if h.Date.Equal(tt.weekendDay) {
+					t.Errorf("weekend holiday %v should be filtered", h)
+				}
+			}
+			if len(c.GetHolidaysNamed(2022)) != 11 {
+				t.Errorf("all holidays should still be listed, actual:%v", c.GetHolidaysNamed(2022))
+			}
+		})
+	}
+}
+
+func TestCalendar_WithWeekend(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	c := New(loc, WithWeekend(time.Friday, time.Saturday))
+	friday := time.Date(2024, time.June, 7, 0, 0, 0, 0, loc)
+	sunday := time.Date(2024, time.June, 9, 0, 0, 0, 0, loc)
+	if c.IsWorkingDay(friday) || c.IsWeekDay(friday) {
+		t.Errorf("%v should be a weekend day", friday)
+	}
+	if !c.IsWorkingDay(sunday) || !c.IsWeekDay(sunday) {
+		t.Errorf("%v should be a working day", sunday)
+	}
+}
+
+func TestCalendar_WithWeekdays(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	c := New(loc, WithWeekdays(time.Monday, time.Tuesday, time.Wednesday, time.Thursday))
+
+	// June 2024 starts on a Saturday, without holiday
+	start := time.Date(2024, time.June, 1, 0, 0, 0, 0, loc)
+	end := time.Date(2024, time.June, 30, 0, 0, 0, 0, loc)
+	days := c.WorkingDays(start, end)
+	for _, d := range days {
+		if d.Weekday() == time.Friday {
+			t.Errorf("Friday %v should not be a working day", d)
+		}
+	}
+	if len(days) != 16 {
+		t.Errorf("bad working days count, expected:16 ; actual:%v", len(days))
+	}
+	if c.CountWorkingDays(start, end) != 16 {
+		t.Errorf("bad working days count, expected:16 ; actual:%v", c.CountWorkingDays(start, end))
+	}
+
+	friday := time.Date(2024, time.June, 7, 0, 0, 0, 0, loc)
+	if c.IsWorkingDay(friday) || c.IsWeekDay(friday) {
+		t.Errorf("%v should not be a working day", friday)
+	}
+	if c.IsHoliday(friday) {
+		t.Errorf("%v should not be a holiday", friday)
+	}
+	if thursday := friday.AddDate(0, 0, -1); !c.IsWorkingDay(thursday) {
+		t.Errorf("%v should be a working day", thursday)
+	}
+}
+
+func TestCalendar_WithSaturdayWorking(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	saturday := time.Date(2024, time.June, 8, 0, 0, 0, 0, loc)
+	sunday := time.Date(2024, time.June, 9, 0, 0, 0, 0, loc)
+	// 11 November 2023 is a Saturday
+	holiday := time.Date(2023, time.November, 11, 0, 0, 0, 0, loc)
+
+	c := New(loc, WithSaturdayWorking(true))
+	if !c.IsWorkingDay(saturday) || !c.IsWeekDay(saturday) {
+		t.Errorf("%v should be a working day", saturday)
+	}
+	if c.IsWorkingDay(sunday) || c.IsWeekDay(sunday) {
+		t.Errorf("%v should not be a working day", sunday)
+	}
+	if c.IsWorkingDay(holiday) {
+		t.Errorf("holiday %v should not be a working day", holiday)
+	}
+	if !defaultWeekend[time.Saturday] {
+		t.Error("default weekend should not be changed")
+	}
+
+	c = New(loc, WithWeekdays(time.Monday, time.Tuesday, time.Wednesday, time.Thursday), WithSaturdayWorking(true))
+	friday := time.Date(2024, time.June, 7, 0, 0, 0, 0, loc)
+	if c.IsWorkingDay(friday) || !c.IsWorkingDay(saturday) || c.IsWorkingDay(sunday) {
+		t.Errorf("Saturday should be added to the working weekdays")
+	}
+
+	c = New(loc, WithSaturdayWorking(false))
+	if c.IsWorkingDay(saturday) {
+		t.Errorf("%v should not be a working day", saturday)
+	}
+}
@@ -0,0 +1,68 @@
+package calendar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// workingHoursLayout is the layout of the bounds of working hours
+const workingHoursLayout = "15:04"
+
+// WorkingHours is a time-of-day window, Start included and End excluded, as durations since midnight
+type WorkingHours struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// ParseWorkingHours parses working hours such as 09:00-18:00
+func ParseWorkingHours(value string) (WorkingHours, error) {
+	bounds := strings.Split(value, "-")
+	if len(bounds) != 2 {
+		return WorkingHours{}, fmt.Errorf("invalid working hours '%v', expected HH:MM-HH:MM", value)
+	}
+	start, err := time.Parse(workingHoursLayout, strings.TrimSpace(bounds[0]))
+	if err != nil {
+		return WorkingHours{}, fmt.Errorf("invalid start of working hours '%v', expected HH:MM", bounds[0])
+	}
+	end, err := time.Parse(workingHoursLayout, strings.TrimSpace(bounds[1]))
+	if err != nil {
+		return WorkingHours{}, fmt.Errorf("invalid end of working hours '%v', expected HH:MM", bounds[1])
+	}
+	hours := WorkingHours{Start: timeOfDay(start), End: timeOfDay(end)}
+	if hours.End <= hours.Start {
+		return WorkingHours{}, fmt.Errorf("invalid working hours '%v', end must be after start", value)
+	}
+	return hours, nil
+}
+
+// WithWorkingHours sets the working hours of working days, the whole day by default
+func WithWorkingHours(hours WorkingHours) Option {
+	return func(calendar *Calendar) {
+		calendar.workingHours = &hours
+	}
+}
+
+// timeOfDay returns the wall clock time of t since midnight, unaffected by DST changes of the day
+func timeOfDay(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+}
+
+// IsWorkingHours returns true if t is within the working hours, see WithWorkingHours, of a working day, in the
+// calendar location
+func (cal *Calendar) IsWorkingHours(t time.Time) bool {
+	local := t.In(cal.Location)
+	if !cal.IsWorkingDay(local) {
+		return false
+	}
+	if cal.workingHours == nil {
+		return true
+	}
+	tod := timeOfDay(local)
+	return tod >= cal.workingHours.Start && tod < cal.workingHours.End
+}
+
+// IsWorkingHoursNow returns true if the current time, see WithClock, is within working hours, see IsWorkingHours
+func (cal *Calendar) IsWorkingHoursNow() bool {
+	return cal.IsWorkingHours(cal.Now())
+}
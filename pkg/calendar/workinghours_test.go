@@ -0,0 +1,68 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseWorkingHours(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    WorkingHours
+		wantErr bool
+	}{
+		{value: "09:00-18:00", want: WorkingHours{Start: 9 * time.Hour, End: 18 * time.Hour}},
+		{value: "08:30 - 12:15", want: WorkingHours{Start: 8*time.Hour + 30*time.Minute, End: 12*time.Hour + 15*time.Minute}},
+		{value: "18:00-09:00", wantErr: true},
+		{value: "09:00-09:00", wantErr: true},
+		{value: "09:00", wantErr: true},
+		{value: "9h-18h", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseWorkingHours(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseWorkingHours() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("bad working hours, expected:%v ; actual:%v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCalendar_IsWorkingHours(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	c := New(loc, WithWorkingHours(WorkingHours{Start: 9 * time.Hour, End: 18 * time.Hour}))
+
+	tests := []struct {
+		name string
+		t    time.Time
+		want bool
+	}{
+		{name: "Before start", t: time.Date(2024, time.May, 6, 8, 59, 59, 0, loc), want: false},
+		{name: "Start", t: time.Date(2024, time.May, 6, 9, 0, 0, 0, loc), want: true},
+		{name: "Before end", t: time.Date(2024, time.May, 6, 17, 59, 59, 0, loc), want: true},
+		{name: "End", t: time.Date(2024, time.May, 6, 18, 0, 0, 0, loc), want: false},
+		{name: "UTC time", t: time.Date(2024, time.May, 6, 7, 0, 0, 0, time.UTC), want: true},
+		{name: "Saturday", t: time.Date(2024, time.May, 4, 10, 0, 0, 0, loc), want: false},
+		{name: "Sunday", t: time.Date(2024, time.May, 5, 10, 0, 0, 0, loc), want: false},
+		{name: "Holiday", t: time.Date(2024, time.May, 8, 10, 0, 0, 0, loc), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.IsWorkingHours(tt.t); got != tt.want {
+				t.Errorf("bad working hours status of %v, expected:%v ; actual:%v", tt.t, tt.want, got)
+			}
+		})
+	}
+
+	c = New(loc)
+	if !c.IsWorkingHours(time.Date(2024, time.May, 6, 3, 0, 0, 0, loc)) {
+		t.Errorf("working days should be working hours without working hours configured")
+	}
+}
@@ -0,0 +1,142 @@
+package params
+
+import (
+	"domogeek/pkg/calendar"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateLayout is the layout of date parameters, years being limited to the ones of calendar.ValidYear
+const DateLayout = "2006-01-02"
+
+var (
+	ErrMissing    = errors.New("missing parameter")
+	ErrInvalid    = errors.New("invalid parameter")
+	ErrOutOfRange = errors.New("parameter out of range")
+)
+
+// Error describes why a query parameter is rejected, it wraps ErrMissing, ErrInvalid or ErrOutOfRange
+type Error struct {
+	Param  string
+	Reason string
+	Err    error
+}
+
+func (e *Error) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("%v '%v'", e.Err, e.Param)
+	}
+	return fmt.Sprintf("%v '%v': %v", e.Err, e.Param, e.Reason)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func required(r *http.Request, name string) (string, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return "", &Error{Param: name, Err: ErrMissing}
+	}
+	return value, nil
+}
+
+// Date parses the required name parameter as a YYYY-MM-DD date at midnight in loc
+func Date(r *http.Request, name string, loc *time.Location) (time.Time, error) {
+	value, err := required(r, name)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return ParseDate(name, value, loc)
+}
+
+// ParseDate parses value, such as a path segment, as a YYYY-MM-DD date at midnight in loc, errors refer to name
+func ParseDate(name, value string, loc *time.Location) (time.Time, error) {
+	date, err := time.ParseInLocation(DateLayout, value, loc)
+	if err != nil {
+		return time.Time{}, &Error{Param: name, Reason: "expected format is YYYY-MM-DD", Err: ErrInvalid}
+	}
+	if date.Year() < calendar.MinYear {
+		return time.Time{}, &Error{Param: name, Reason: fmt.Sprintf("expected year from %d", calendar.MinYear), Err: ErrOutOfRange}
+	}
+	return date, nil
+}
+
+// OptionalDate parses the name parameter as Date, def is returned when the parameter is missing
+func OptionalDate(r *http.Request, name string, loc *time.Location, def time.Time) (time.Time, error) {
+	if r.URL.Query().Get(name) == "" {
+		return def, nil
+	}
+	return Date(r, name, loc)
+}
+
+// Int parses the required name parameter as an integer within [min, max]
+func Int(r *http.Request, name string, min, max int) (int, error) {
+	value, err := required(r, name)
+	if err != nil {
+		return 0, err
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, &Error{Param: name, Reason: "integer expected", Err: ErrInvalid}
+	}
+	if i < min || i > max {
+		return 0, &Error{Param: name, Reason: fmt.Sprintf("expected between %d and %d", min, max), Err: ErrOutOfRange}
+	}
+	return i, nil
+}
+
+// OptionalInt parses the name parameter as Int, def is returned when the parameter is missing
+func OptionalInt(r *http.Request, name string, min, max, def int) (int, error) {
+	if r.URL.Query().Get(name) == "" {
+		return def, nil
+	}
+	return Int(r, name, min, max)
+}
+
+// Year parses the required name parameter as a year within [calendar.MinYear, calendar.MaxYear]
+func Year(r *http.Request, name string) (int, error) {
+	return Int(r, name, calendar.MinYear, calendar.MaxYear)
+}
+
+// OptionalYear parses the name parameter as Year, def is returned when the parameter is missing
+func OptionalYear(r *http.Request, name string, def int) (int, error) {
+	if r.URL.Query().Get(name) == "" {
+		return def, nil
+	}
+	return Year(r, name)
+}
+
+// Month parses the required name parameter as a month number, 1 to 12
+func Month(r *http.Request, name string) (time.Month, error) {
+	m, err := Int(r, name, int(time.January), int(time.December))
+	return time.Month(m), err
+}
+
+// Language returns the name parameter, or the first language of the Accept-Language header, in header order, within
+// supported, or def. The name parameter must be one of supported.
+func Language(r *http.Request, name string, supported []string, def string) (string, error) {
+	if value := r.URL.Query().Get(name); value != "" {
+		for _, lang := range supported {
+			if value == lang {
+				return value, nil
+			}
+		}
+		return "", &Error{Param: name, Reason: "expected one of " + strings.Join(supported, ", "), Err: ErrInvalid}
+	}
+
+	for _, tag := range strings.Split(r.Header.Get("Accept-Language"), ",") {
+		tag = strings.TrimSpace(strings.SplitN(tag, ";", 2)[0])
+		primary := strings.ToLower(strings.SplitN(tag, "-", 2)[0])
+		for _, lang := range supported {
+			if primary == lang {
+				return lang, nil
+			}
+		}
+	}
+	return def, nil
+}
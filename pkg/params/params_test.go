@@ -0,0 +1,151 @@
+package params
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDate(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Errorf("unable to load time location: %v", err)
+		t.Fail()
+	}
+	tests := []struct {
+		name    string
+		url     string
+		want    time.Time
+		wantErr error
+	}{
+		{"Valid date", "/?date=2024-05-08", time.Date(2024, time.May, 8, 0, 0, 0, 0, loc), nil},
+		{"Missing date", "/", time.Time{}, ErrMissing},
+		{"Malformed date", "/?date=08/05/2024", time.Time{}, ErrInvalid},
+		{"Nonexistent date", "/?date=2023-02-29", time.Time{}, ErrInvalid},
+		{"Date before Gregorian calendar", "/?date=1582-12-31", time.Time{}, ErrOutOfRange},
+		{"First date", "/?date=1583-01-01", time.Date(1583, time.January, 1, 0, 0, 0, 0, loc), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Date(httptest.NewRequest("GET", tt.url, nil), "date", loc)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Date() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Date() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOptionalDate(t *testing.T) {
+	def := time.Date(2024, time.May, 8, 0, 0, 0, 0, time.UTC)
+	got, err := OptionalDate(httptest.NewRequest("GET", "/", nil), "date", time.UTC, def)
+	if err != nil || !got.Equal(def) {
+		t.Errorf("OptionalDate() got = (%v, %v), want (%v, nil)", got, err, def)
+	}
+	if _, err := OptionalDate(httptest.NewRequest("GET", "/?date=bad", nil), "date", time.UTC, def); !errors.Is(err, ErrInvalid) {
+		t.Errorf("OptionalDate() error = %v, wantErr %v", err, ErrInvalid)
+	}
+}
+
+func TestOptionalInt(t *testing.T) {
+	got, err := OptionalInt(httptest.NewRequest("GET", "/", nil), "v", 1, 2, 1)
+	if err != nil || got != 1 {
+		t.Errorf("OptionalInt() got = (%v, %v), want (1, nil)", got, err)
+	}
+	got, err = OptionalInt(httptest.NewRequest("GET", "/?v=2", nil), "v", 1, 2, 1)
+	if err != nil || got != 2 {
+		t.Errorf("OptionalInt() got = (%v, %v), want (2, nil)", got, err)
+	}
+	if _, err := OptionalInt(httptest.NewRequest("GET", "/?v=3", nil), "v", 1, 2, 1); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("OptionalInt() error = %v, wantErr %v", err, ErrOutOfRange)
+	}
+}
+
+func TestYear(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		want    int
+		wantErr error
+	}{
+		{"Valid year", "/?year=2024", 2024, nil},
+		{"Missing year", "/", 0, ErrMissing},
+		{"Malformed year", "/?year=twenty", 0, ErrInvalid},
+		{"Year too small", "/?year=0", 0, ErrOutOfRange},
+		{"Year before Gregorian calendar", "/?year=1582", 0, ErrOutOfRange},
+		{"First year", "/?year=1583", 1583, nil},
+		{"Last year", "/?year=9999", 9999, nil},
+		{"Year too large", "/?year=10000", 0, ErrOutOfRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Year(httptest.NewRequest("GET", tt.url, nil), "year")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Year() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Year() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMonth(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		want    time.Month
+		wantErr error
+	}{
+		{"Valid month", "/?month=5", time.May, nil},
+		{"Missing month", "/", 0, ErrMissing},
+		{"Month out of range", "/?month=13", 0, ErrOutOfRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Month(httptest.NewRequest("GET", tt.url, nil), "month")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Month() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Month() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLanguage(t *testing.T) {
+	supported := []string{"fr", "en"}
+	tests := []struct {
+		name           string
+		url            string
+		acceptLanguage string
+		want           string
+		wantErr        error
+	}{
+		{"Default", "/", "", "fr", nil},
+		{"Parameter", "/?lang=en", "fr-FR", "en", nil},
+		{"Unsupported parameter", "/?lang=de", "", "", ErrInvalid},
+		{"Header", "/", "en-GB,en;q=0.9", "en", nil},
+		{"Header first supported", "/", "de-DE, en-US;q=0.8, fr;q=0.5", "en", nil},
+		{"Header unsupported", "/", "de-DE", "fr", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			if tt.acceptLanguage != "" {
+				r.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			got, err := Language(r, "lang", supported, "fr")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Language() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Language() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}